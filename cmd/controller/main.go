@@ -1,7 +1,11 @@
 package main
 
 import (
+	"flag"
 	"fmt"
+	"time"
+
+	"golang.org/x/time/rate"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
@@ -10,14 +14,42 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/manager/signals"
 
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
 	"github.com/cirocosta/pizza-controller/pkg/reconciler"
 )
 
+var (
+	defaultCountry = flag.String("default-country", string(dominos.CountryCanada),
+		"country assumed for customers whose country can't be determined (CA or US)")
+	dominosURL = flag.String("dominos-url", "",
+		"base URL of the Domino's API to use for all customers, overriding the per-country one")
+	dominosRequestTimeout = flag.Duration("dominos-request-timeout", dominos.DefaultRequestTimeout,
+		"how long each request to the Domino's API can take before it's given up on")
+	dominosMaxAttempts = flag.Int("dominos-max-attempts", 3,
+		"maximum number of attempts made for requests to the Domino's API that are safe to retry")
+	dominosRateLimit = flag.Float64("dominos-rate-limit", 2,
+		"maximum number of requests per second made to the Domino's API (0 for no limit)")
+	dominosRateBurst = flag.Int("dominos-rate-burst", 5,
+		"maximum number of requests made to the Domino's API at once, above the rate limit")
+	dominosDebug = flag.Bool("dominos-debug", false,
+		"log the requests made to the Domino's API and the responses to them (redacted) at verbosity level 1")
+	dominosHARDir = flag.String("dominos-har-dir", "",
+		"directory to capture the requests made to the Domino's API into, as HAR files (requires --dominos-debug)")
+	maxNearbyStores = flag.Int("max-nearby-stores", reconciler.DefaultMaxNearbyStores,
+		"maximum number of stores nearby each customer to create PizzaStore objects for, the closest ones first (0 for no limit)")
+	metricsAddr = flag.String("metrics-addr", ":8080",
+		"address that the prometheus metrics endpoint binds to (0 to disable it)")
+)
+
 func init() {
 	log.SetLogger(zap.New(zap.UseDevMode(true)))
 }
 
 func run() error {
+	if _, err := dominos.URLForCountry(dominos.Country(*defaultCountry)); err != nil {
+		return fmt.Errorf("default country: %w", err)
+	}
+
 	scheme := runtime.NewScheme()
 
 	if err := reconciler.AddToScheme(scheme); err != nil {
@@ -25,14 +57,31 @@ func run() error {
 	}
 
 	mgr, err := manager.New(config.GetConfigOrDie(), manager.Options{
-		MetricsBindAddress: "0",
+		MetricsBindAddress: *metricsAddr,
 		Scheme:             scheme,
 	})
 	if err != nil {
 		return fmt.Errorf("new manager: %w", err)
 	}
 
-	if err := reconciler.RegisterReconcilers(mgr); err != nil {
+	dominosConfig := dominos.Config{
+		DefaultCountry: dominos.Country(*defaultCountry),
+		URL:            *dominosURL,
+		RequestTimeout: *dominosRequestTimeout,
+		Debug:          *dominosDebug,
+		HARDirectory:   *dominosHARDir,
+		RetryPolicy: dominos.ExponentialBackoff{
+			MaxAttempts: *dominosMaxAttempts,
+			BaseDelay:   500 * time.Millisecond,
+			MaxDelay:    5 * time.Second,
+		},
+	}
+
+	if *dominosRateLimit > 0 {
+		dominosConfig.RateLimiter = rate.NewLimiter(rate.Limit(*dominosRateLimit), *dominosRateBurst)
+	}
+
+	if err := reconciler.RegisterReconcilers(mgr, dominosConfig, *maxNearbyStores); err != nil {
 		return fmt.Errorf("register reconcilers: %w", err)
 	}
 
@@ -44,6 +93,8 @@ func run() error {
 }
 
 func main() {
+	flag.Parse()
+
 	entryLog := log.Log.WithName("entrypoint")
 	entryLog.Info("initializing")
 
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// writerLogger is a minimal logr.Logger writing one line per entry, at all
+// verbosity levels - pizzactl only logs at all when debugging.
+type writerLogger struct {
+	w      io.Writer
+	name   string
+	values []interface{}
+}
+
+var _ logr.Logger = writerLogger{}
+
+func (l writerLogger) Enabled() bool { return true }
+
+func (l writerLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.write("INFO", msg, keysAndValues)
+}
+
+func (l writerLogger) Error(err error, msg string, keysAndValues ...interface{}) {
+	l.write("ERROR", msg, append(keysAndValues, "error", err))
+}
+
+func (l writerLogger) V(level int) logr.Logger { return l }
+
+func (l writerLogger) WithValues(keysAndValues ...interface{}) logr.Logger {
+	values := make([]interface{}, 0, len(l.values)+len(keysAndValues))
+	l.values = append(append(values, l.values...), keysAndValues...)
+
+	return l
+}
+
+func (l writerLogger) WithName(name string) logr.Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+
+	l.name = name
+	return l
+}
+
+func (l writerLogger) write(level, msg string, keysAndValues []interface{}) {
+	b := &strings.Builder{}
+	fmt.Fprintf(b, "%s\t%s\t", time.Now().Format(time.RFC3339), level)
+
+	if l.name != "" {
+		fmt.Fprintf(b, "%s\t", l.name)
+	}
+
+	b.WriteString(msg)
+
+	all := append(append([]interface{}{}, l.values...), keysAndValues...)
+	for idx := 0; idx < len(all); idx += 2 {
+		var value interface{} = "(missing)"
+		if idx+1 < len(all) {
+			value = all[idx+1]
+		}
+
+		fmt.Fprintf(b, "\t%v=%v", all[idx], value)
+	}
+
+	b.WriteString("\n")
+	io.WriteString(l.w, b.String())
+}
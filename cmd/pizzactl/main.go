@@ -0,0 +1,143 @@
+// pizzactl explores (and orders from) Domino's API straight from the
+// command line, without a Kubernetes cluster.
+//
+//	pizzactl [flags] stores --zip M5V0J4 --street-number 90 ...
+//	pizzactl [flags] menu [--search pepperoni] [--output json] <store>
+//	pizzactl [flags] price -f order.yaml
+//	pizzactl [flags] place -f order.yaml
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/go-logr/logr"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+// flags are the global flags, kept apart from flag.CommandLine so that any
+// registered by the packages imported don't show up.
+var flags = flag.NewFlagSet("pizzactl", flag.ContinueOnError)
+
+var (
+	country = flags.String("country", "",
+		"country of the customer (CA or US) - derived from the zip code when not set, CA otherwise")
+	dominosURL = flags.String("dominos-url", "",
+		"base URL of the Domino's API to use, overriding the per-country one")
+	language = flags.String("language", "",
+		"language to retrieve menus in (e.g., fr) - French for Quebec, English otherwise, when not set")
+	requestTimeout = flags.Duration("request-timeout", dominos.DefaultRequestTimeout,
+		"how long each request to the Domino's API can take before it's given up on")
+	debug = flags.Bool("debug", false,
+		"log the requests made to the Domino's API and the responses to them (redacted) to stderr")
+	harDir = flags.String("har-dir", "",
+		"directory to capture the requests made to the Domino's API into, as HAR files (requires --debug)")
+)
+
+// commandTimeout is how long a whole command can take, regardless of how
+// many requests to Domino's it involves.
+const commandTimeout = 2 * time.Minute
+
+// command is one of the subcommands of pizzactl.
+type command struct {
+	name        string
+	description string
+	run         func(args []string) error
+}
+
+var commands = []command{
+	{"stores", "list the stores nearby an address", runStores},
+	{"menu", "show (and search) the menu of a store", runMenu},
+	{"price", "price the order described in a file", runPrice},
+	{"place", "place the order described in a file", runPlace},
+}
+
+func usage() {
+	out := flags.Output()
+
+	fmt.Fprintf(out, "usage: pizzactl [flags] <command> [command flags] [args]\n\n")
+	fmt.Fprintf(out, "commands:\n")
+	for _, cmd := range commands {
+		fmt.Fprintf(out, "  %-8s%s\n", cmd.name, cmd.description)
+	}
+
+	fmt.Fprintf(out, "\nflags:\n")
+	flags.PrintDefaults()
+}
+
+// newClient instantiates a Domino's client for the API serving a customer,
+// as configured through the global flags - the same way that the controller
+// does for PizzaCustomer objects.
+func newClient(customer v1alpha1.PizzaCustomerSpec) (*dominos.Client, error) {
+	obj := &v1alpha1.PizzaCustomer{Spec: customer}
+	if *country != "" {
+		obj.Spec.Country = *country
+	}
+
+	config := dominos.Config{
+		DefaultCountry: dominos.CountryCanada,
+		URL:            *dominosURL,
+		RequestTimeout: *requestTimeout,
+		Debug:          *debug,
+	}
+
+	opts := []dominos.Option{
+		dominos.WithUserAgent("pizzactl"),
+	}
+	if *language != "" {
+		opts = append(opts, dominos.WithLanguage(*language))
+	}
+	if *debug {
+		config.HARDirectory = *harDir
+		opts = append(opts, dominos.WithLogger(newLogger()))
+	}
+
+	return config.NewClient(obj, opts...)
+}
+
+func newContext() (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.Background(), commandTimeout)
+}
+
+func newLogger() logr.Logger {
+	return writerLogger{w: os.Stderr}
+}
+
+func run() error {
+	flags.Usage = usage
+	if err := flags.Parse(os.Args[1:]); err != nil {
+		return err
+	}
+
+	if flags.NArg() == 0 {
+		usage()
+		return fmt.Errorf("missing command")
+	}
+
+	name, args := flags.Arg(0), flags.Args()[1:]
+	for _, cmd := range commands {
+		if cmd.name == name {
+			return cmd.run(args)
+		}
+	}
+
+	usage()
+	return fmt.Errorf("unknown command '%s'", name)
+}
+
+func main() {
+	if err := run(); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+// menuItem is an entry of a menu, regardless of what kind of entry it is.
+type menuItem struct {
+	Kind        string `json:"kind"`
+	Code        string `json:"code"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Price       string `json:"price,omitempty"`
+}
+
+// menuKinds are the kinds of entries of a menu, in the order they're listed
+// in.
+var menuKinds = []string{"product", "variant", "preconfigured", "coupon", "topping", "side"}
+
+func runMenu(args []string) error {
+	fs := flag.NewFlagSet("menu", flag.ContinueOnError)
+
+	var (
+		search = fs.String("search", "",
+			"only show entries whose code, name or description contain this (case insensitive)")
+		kind = fs.String("kind", "",
+			"only show entries of this kind ("+strings.Join(menuKinds, ", ")+")")
+		output = fs.String("output", "table", "output format (table or json)")
+	)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "usage: menu [flags] <store>\n\nflags:\n")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return fmt.Errorf("expected the id of a store")
+	}
+
+	if *kind != "" && !contains(menuKinds, *kind) {
+		return fmt.Errorf("unknown kind '%s'", *kind)
+	}
+
+	client, err := newClient(v1alpha1.PizzaCustomerSpec{})
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	storeID := fs.Arg(0)
+	menu, err := client.StoreMenu(ctx, storeID)
+	if err != nil {
+		return fmt.Errorf("store '%s' menu: %w", storeID, err)
+	}
+
+	items := []menuItem{}
+	for _, item := range menuItems(menu) {
+		if *kind != "" && item.Kind != *kind {
+			continue
+		}
+
+		if *search != "" && !matches(item, *search) {
+			continue
+		}
+
+		items = append(items, item)
+	}
+
+	switch *output {
+	case "json":
+		return printJSON(items)
+	case "table":
+	default:
+		return fmt.Errorf("unknown output format '%s'", *output)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "KIND\tCODE\tPRICE\tNAME")
+
+	for _, item := range items {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", item.Kind, item.Code, item.Price, item.Name)
+	}
+
+	return w.Flush()
+}
+
+// menuItems flattens a menu into the list of its entries.
+func menuItems(menu *dominos.Menu) []menuItem {
+	items := []menuItem{}
+
+	for _, product := range menu.Products {
+		items = append(items, menuItem{"product", product.Code, product.Name, product.Description, ""})
+	}
+
+	for _, variant := range menu.Variants {
+		items = append(items, menuItem{"variant", variant.Code, variant.Name, "", variant.Price})
+	}
+
+	for _, product := range menu.Preconfigured {
+		items = append(items, menuItem{"preconfigured", product.ID, product.Name, product.Description, ""})
+	}
+
+	for _, coupon := range menu.Coupons {
+		items = append(items, menuItem{"coupon", coupon.Code, coupon.Name, coupon.Description, coupon.Price})
+	}
+
+	for _, topping := range menu.Toppings {
+		items = append(items, menuItem{"topping", topping.Code, topping.Name, topping.Description, ""})
+	}
+
+	for _, side := range menu.Sides {
+		items = append(items, menuItem{"side", side.Code, side.Name, side.Description, ""})
+	}
+
+	return items
+}
+
+func matches(item menuItem, search string) bool {
+	search = strings.ToLower(search)
+
+	for _, field := range []string{item.Code, item.Name, item.Description} {
+		if strings.Contains(strings.ToLower(field), search) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, str string) bool {
+	for _, item := range list {
+		if item == str {
+			return true
+		}
+	}
+
+	return false
+}
+
+func printJSON(v interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(v)
+}
@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"sigs.k8s.io/yaml"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+// OrderFile describes an order to be priced (or placed) by pizzactl, using
+// the same fields as the spec of PizzaCustomer and PizzaOrder objects.
+//
+//	store: "10391"
+//	customer:
+//	  firstName: barack
+//	  ...
+//	order:
+//	  serviceMethod: Carryout
+//	  paymentType: DoorCredit
+//	  products:
+//	    - id: 10SCREEN
+//	creditCard:
+//	  cardType: visa
+//	  ...
+type OrderFile struct {
+	// Store is the ID of the store to order from.
+	Store string `json:"store"`
+
+	Customer v1alpha1.PizzaCustomerSpec `json:"customer"`
+
+	// Order is what to order, with `storeRef` and `customerRef` being
+	// ignored (see Store and Customer instead).
+	Order v1alpha1.PizzaOrderSpec `json:"order"`
+
+	// CreditCard holds, for orders paid for with a credit card, the same
+	// fields as the secret referenced by `creditCardSecretRef`.
+	CreditCard map[string]string `json:"creditCard,omitempty"`
+
+	// GiftCard holds, for orders paid for with a gift card, the same
+	// fields as the secret referenced by `giftCardSecretRef`.
+	GiftCard map[string]string `json:"giftCard,omitempty"`
+}
+
+func runPrice(args []string) error {
+	fs := flag.NewFlagSet("price", flag.ContinueOnError)
+
+	var (
+		fname  = fs.String("f", "", "file describing the order (see OrderFile)")
+		output = fs.String("output", "table", "output format (table or json)")
+	)
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := readOrderFile(*fname)
+	if err != nil {
+		return err
+	}
+
+	client, err := newClient(file.Customer)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	_, price, err := priceOrder(ctx, client, file)
+	if err != nil {
+		return err
+	}
+
+	return printPrice(price, *output)
+}
+
+func runPlace(args []string) error {
+	fs := flag.NewFlagSet("place", flag.ContinueOnError)
+
+	fname := fs.String("f", "", "file describing the order (see OrderFile)")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	file, err := readOrderFile(*fname)
+	if err != nil {
+		return err
+	}
+
+	// just like with PizzaOrder objects, placing an order takes being
+	// explicit about it, and having agreed to the price beforehand.
+	//
+	if !file.Order.YeahSurePlaceTheOrder {
+		return fmt.Errorf("'order.yeahSurePlaceTheOrder' must be set for the order to be placed")
+	}
+
+	if file.Order.AcknowledgedPrice == "" {
+		return fmt.Errorf("'order.acknowledgedPrice' must be set to the price of the order (see `pizzactl price`)")
+	}
+
+	acknowledged, err := dominos.ParseMoney(file.Order.AcknowledgedPrice)
+	if err != nil {
+		return fmt.Errorf("acknowledged price: %w", err)
+	}
+
+	client, err := newClient(file.Customer)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	order, price, err := priceOrder(ctx, client, file)
+	if err != nil {
+		return err
+	}
+
+	if price.Total != acknowledged {
+		return fmt.Errorf("order priced at %s %s, but acknowledged at %s - not placing it",
+			price.Total, price.Currency, acknowledged,
+		)
+	}
+
+	if err := assemblePayment(order, file); err != nil {
+		return fmt.Errorf("payment: %w", err)
+	}
+
+	order.Amount = price.Total
+
+	placed, err := client.PlaceOrder(ctx, *order)
+	if err != nil {
+		return fmt.Errorf("place order: %w", err)
+	}
+
+	fmt.Printf("placed as order %s (estimated wait: %s minutes)\n",
+		placed.ID, placed.EstimatedWaitMinutes,
+	)
+
+	return nil
+}
+
+func readOrderFile(fname string) (*OrderFile, error) {
+	if fname == "" {
+		return nil, fmt.Errorf("missing order file (-f)")
+	}
+
+	var (
+		content []byte
+		err     error
+	)
+
+	if fname == "-" {
+		content, err = ioutil.ReadAll(os.Stdin)
+	} else {
+		content, err = ioutil.ReadFile(fname)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read '%s': %w", fname, err)
+	}
+
+	file := &OrderFile{}
+	if err := yaml.UnmarshalStrict(content, file); err != nil {
+		return nil, fmt.Errorf("unmarshal '%s': %w", fname, err)
+	}
+
+	if file.Store == "" {
+		return nil, fmt.Errorf("'%s': missing store", fname)
+	}
+
+	return file, nil
+}
+
+// priceOrder prices the order described in a file, making sure beforehand
+// that the store has what's being ordered, and afterwards that the coupons
+// got applied.
+func priceOrder(
+	ctx context.Context,
+	client *dominos.Client,
+	file *OrderFile,
+) (*dominos.Order, *dominos.Price, error) {
+	order, err := assembleOrder(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("assemble order: %w", err)
+	}
+
+	menu, err := client.StoreMenu(ctx, order.StoreID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("store '%s' menu: %w", order.StoreID, err)
+	}
+
+	if err := menu.ValidateProducts(order.Products); err != nil {
+		return nil, nil, fmt.Errorf("invalid products: %w", err)
+	}
+
+	if err := menu.ValidateCoupons(order.Coupons); err != nil {
+		return nil, nil, fmt.Errorf("invalid coupons: %w", err)
+	}
+
+	price, err := client.PriceOrder(ctx, *order)
+	if err != nil {
+		return nil, nil, fmt.Errorf("price order: %w", err)
+	}
+
+	for _, coupon := range price.Coupons {
+		if !coupon.Applied() {
+			return nil, nil, fmt.Errorf("%s", coupon.Message())
+		}
+	}
+
+	return order, price, nil
+}
+
+// assembleOrder converts the order described in a file into the form
+// expected by the Domino's client, the same way that the controller does
+// for PizzaOrder objects (payment details aside).
+func assembleOrder(file *OrderFile) (*dominos.Order, error) {
+	obj := &v1alpha1.PizzaOrder{Spec: file.Order}
+	customer := &v1alpha1.PizzaCustomer{Spec: file.Customer}
+
+	products := []dominos.Product{}
+	for _, product := range file.Order.Products {
+		toppings, err := dominos.AssembleToppings(product)
+		if err != nil {
+			return nil, fmt.Errorf("product '%s': %w", product.ID, err)
+		}
+
+		products = append(products, dominos.Product{
+			ID:       product.ID,
+			Quantity: product.Quantity,
+			Toppings: toppings,
+		})
+	}
+
+	return &dominos.Order{
+		StoreID: file.Store,
+		PersonalInformation: dominos.PersonalInformation{
+			FirstName: file.Customer.FirstName,
+			LastName:  file.Customer.LastName,
+			Email:     file.Customer.Email,
+			Phone:     file.Customer.Phone,
+		},
+		Address:     dominos.CustomerAddress(customer),
+		Products:    products,
+		Coupons:     file.Order.Coupons,
+		Service:     dominos.OrderServiceMethod(obj),
+		PaymentType: dominos.OrderPaymentType(obj),
+	}, nil
+}
+
+// assemblePayment fills the payment details of an order in, from the ones
+// in the file describing it.
+func assemblePayment(order *dominos.Order, file *OrderFile) error {
+	switch order.PaymentType {
+	case dominos.PaymentTypeDoorCredit, dominos.PaymentTypeCreditCard:
+		if file.CreditCard == nil {
+			return fmt.Errorf("missing 'creditCard'")
+		}
+
+		var cardType dominos.CreditCardType
+		switch strings.ToLower(file.CreditCard["cardType"]) {
+		case "mastercard":
+			cardType = dominos.CreditCardTypeMastercard
+		case "visa":
+			cardType = dominos.CreditCardTypeVisa
+		case "amex":
+			cardType = dominos.CreditCardTypeAmex
+		default:
+			return fmt.Errorf("unknown card type '%s'", file.CreditCard["cardType"])
+		}
+
+		order.CreditCard = dominos.CreditCard{
+			Type:         cardType,
+			Number:       file.CreditCard["number"],
+			Expiration:   file.CreditCard["expiration"],
+			SecurityCode: file.CreditCard["securityCode"],
+			PostalCode:   file.CreditCard["zip"],
+		}
+	case dominos.PaymentTypeGiftCard:
+		if file.GiftCard == nil {
+			return fmt.Errorf("missing 'giftCard'")
+		}
+
+		order.GiftCard = dominos.GiftCard{
+			Number: file.GiftCard["number"],
+			PIN:    file.GiftCard["pin"],
+		}
+	}
+
+	return nil
+}
+
+func printPrice(price *dominos.Price, output string) error {
+	switch output {
+	case "json":
+		return printJSON(dominos.AssemblePriceStatus(price))
+	case "table":
+	default:
+		return fmt.Errorf("unknown output format '%s'", output)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', tabwriter.AlignRight)
+	for _, line := range []struct {
+		name   string
+		amount dominos.Money
+	}{
+		{"subtotal", price.Subtotal},
+		{"discounts", price.Discounts},
+		{"fees", price.Fees},
+		{"tax", price.Tax},
+		{"total", price.Total},
+	} {
+		if line.amount == 0 && line.name != "total" && line.name != "subtotal" {
+			continue
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t\n", line.name, line.amount, price.Currency)
+	}
+
+	return w.Flush()
+}
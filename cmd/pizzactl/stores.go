@@ -0,0 +1,78 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+func runStores(args []string) error {
+	fs := flag.NewFlagSet("stores", flag.ContinueOnError)
+
+	var (
+		customer = v1alpha1.PizzaCustomerSpec{}
+		service  = fs.String("service", string(dominos.ServiceCarryout),
+			"service method that the stores must be open for (Delivery, Carryout or DriveUpCarryout)")
+		output = fs.String("output", "table", "output format (table or json)")
+	)
+
+	fs.StringVar(&customer.StreetNumber, "street-number", "", "street number of the address")
+	fs.StringVar(&customer.StreetName, "street-name", "", "street name of the address")
+	fs.StringVar(&customer.City, "city", "", "city of the address")
+	fs.StringVar(&customer.State, "state", "", "state (or province) of the address, e.g. ON")
+	fs.StringVar(&customer.Zip, "zip", "", "zip (or postal) code of the address")
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if customer.Zip == "" && customer.City == "" {
+		return fmt.Errorf("either --zip or --city must be set")
+	}
+
+	client, err := newClient(customer)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := newContext()
+	defer cancel()
+
+	stores, err := client.StoresNearby(ctx,
+		dominos.CustomerAddress(&v1alpha1.PizzaCustomer{Spec: customer}),
+		dominos.Service(*service),
+	)
+	if err != nil {
+		return fmt.Errorf("stores nearby: %w", err)
+	}
+
+	switch *output {
+	case "json":
+		return printJSON(stores)
+	case "table":
+	default:
+		return fmt.Errorf("unknown output format '%s'", *output)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tPHONE\tSERVICES\tADDRESS")
+
+	for _, store := range stores {
+		services := []string{}
+		for _, service := range store.Services {
+			services = append(services, string(service))
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
+			store.ID, store.Phone, strings.Join(services, ","),
+			strings.Join(strings.Fields(store.Address), " "),
+		)
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,77 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Amount",type=string,JSONPath=`.spec.amount`
+// +kubebuilder:printcolumn:name="Period",type=string,JSONPath=`.spec.period`
+// +kubebuilder:printcolumn:name="Consumed",type=string,JSONPath=`.status.consumed`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PizzaBudget limits how much the orders in its namespace can add up to over
+// a period of time.
+type PizzaBudget struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PizzaBudgetSpec   `json:"spec,omitempty"`
+	Status PizzaBudgetStatus `json:"status,omitempty"`
+}
+
+type PizzaBudgetSpec struct {
+	// Amount is how much can be spent on orders placed within a period.
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	Amount string `json:"amount"`
+
+	// Period is how often the budget is renewed, starting at midnight
+	// (UTC) of every day, of every Monday, or of the first day of every
+	// month.
+	//
+	// +kubebuilder:validation:Enum=Daily;Weekly;Monthly
+	// +kubebuilder:default=Monthly
+	// +optional
+	Period string `json:"period,omitempty"`
+
+	// Currency is the currency that the amount is in (e.g., CAD). When
+	// set, only orders priced in that currency count towards the budget.
+	//
+	// +optional
+	Currency string `json:"currency,omitempty"`
+}
+
+type PizzaBudgetStatus struct {
+	// PeriodStart is when the current period started.
+	//
+	// +optional
+	PeriodStart *metav1.Time `json:"periodStart,omitempty"`
+
+	// Consumed is how much has been spent on orders placed within the
+	// current period.
+	//
+	// +optional
+	Consumed string `json:"consumed,omitempty"`
+
+	// Remaining is how much can still be spent within the current period.
+	//
+	// +optional
+	Remaining string `json:"remaining,omitempty"`
+
+	// +optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type PizzaBudgetList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PizzaBudget `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PizzaBudget{}, &PizzaBudgetList{})
+}
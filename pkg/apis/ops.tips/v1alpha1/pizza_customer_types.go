@@ -31,12 +31,35 @@ type PizzaCustomerSpec struct {
 	State        string `json:"state"`
 	Zip          string `json:"zip"`
 
-	CreditCardSecretRef corev1.LocalObjectReference `json:"creditCardSecretRef"`
+	// Country determines which Domino's API the customer is served by. When
+	// not set, it's derived from the format of the zip code, falling back to
+	// the controller's default.
+	//
+	// +kubebuilder:validation:Enum=CA;US
+	// +optional
+	Country string `json:"country,omitempty"`
+
+	// CreditCardSecretRef points at the secret with the details of the
+	// credit card used for the DoorCredit and CreditCard payment types.
+	//
+	// +optional
+	CreditCardSecretRef corev1.LocalObjectReference `json:"creditCardSecretRef,omitempty"`
+
+	// GiftCardSecretRef points at the secret with the `number` and `pin`
+	// of the gift card used for the GiftCard payment type.
+	//
+	// +optional
+	GiftCardSecretRef corev1.LocalObjectReference `json:"giftCardSecretRef,omitempty"`
 }
 
 type PizzaCustomerStatus struct {
 	ClosestStoreRef corev1.LocalObjectReference `json:"closestStoreRef,omitempty"`
-	Conditions      []metav1.Condition          `json:"conditions,omitempty"`
+
+	// StoreRefs points at the stores found nearby the customer, from the
+	// closest to the farthest.
+	StoreRefs []corev1.LocalObjectReference `json:"storeRefs,omitempty"`
+
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -7,8 +7,9 @@ import (
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
-// +kubebuilder:printcolumn:name="Price",type=string,JSONPath=`.status.price`
+// +kubebuilder:printcolumn:name="Price",type=string,JSONPath=`.status.price.total`
 // +kubebuilder:printcolumn:name="ID",type=string,JSONPath=`.status.orderID`
+// +kubebuilder:printcolumn:name="Stage",type=string,JSONPath=`.status.stage`
 // +kubebuilder:printcolumn:name="Condition",type=string,JSONPath=`.status.conditions[-1].type`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
@@ -21,22 +22,186 @@ type PizzaOrder struct {
 }
 
 type PizzaOrderSpec struct {
-	YeahSurePlaceTheOrder bool                        `json:"yeahSurePlaceTheOrder,omitempty"`
-	PaymentType           string                      `json:"paymentType"`
-	StoreRef              corev1.LocalObjectReference `json:"storeRef"`
-	CustomerRef           corev1.LocalObjectReference `json:"customerRef"`
-	Products              []PizzaOrderProduct         `json:"products"`
+	YeahSurePlaceTheOrder bool `json:"yeahSurePlaceTheOrder,omitempty"`
+
+	// AcknowledgedPrice is the price that the customer agrees to pay for
+	// the order. The order only gets placed once it matches the total
+	// that it got priced at (`status.price.total`).
+	//
+	// +optional
+	AcknowledgedPrice string `json:"acknowledgedPrice,omitempty"`
+
+	// MaxPrice is the maximum price that the order can be placed for.
+	// PizzaPolicy objects in the namespace might set a lower one.
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	MaxPrice string `json:"maxPrice,omitempty"`
+
+	// PaymentType is how the order is paid for:
+	//
+	// - Cash: cash at the door (or at the store)
+	// - DoorCredit: credit card at the door (or at the store)
+	// - CreditCard: credit card, online, as the order is placed
+	// - GiftCard: Domino's gift card, online, as the order is placed
+	//
+	// It must be one that the store accepts.
+	//
+	// +kubebuilder:validation:Enum=Cash;DoorCredit;CreditCard;GiftCard
+	// +kubebuilder:default=DoorCredit
+	// +optional
+	PaymentType string `json:"paymentType,omitempty"`
+
+	StoreRef    corev1.LocalObjectReference `json:"storeRef"`
+	CustomerRef corev1.LocalObjectReference `json:"customerRef"`
+	Products    []PizzaOrderProduct         `json:"products"`
+
+	// Coupons are the codes of the coupons (from the store's
+	// `spec.coupons`) to apply to the order. The order doesn't get priced
+	// (nor placed) if any of them doesn't apply.
+	//
+	// +optional
+	Coupons []string `json:"coupons,omitempty"`
+
+	// ServiceMethod is how the order gets to the customer. It must be
+	// one that the store takes orders for.
+	//
+	// +kubebuilder:validation:Enum=Delivery;Carryout;DriveUpCarryout
+	// +kubebuilder:default=Carryout
+	// +optional
+	ServiceMethod string `json:"serviceMethod,omitempty"`
 }
 
 type PizzaOrderProduct struct {
-	ID       string `json:"id"`
-	Quantity int    `json:"quantity"`
+	// ID is the code of the product (or of the variant of a product, like
+	// a specific size and crust of pizza) to order.
+	ID string `json:"id"`
+
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=25
+	// +kubebuilder:default=1
+	// +optional
+	Quantity int `json:"quantity,omitempty"`
+
+	// Toppings to add to the product, or whose default amount should be
+	// changed.
+	//
+	// +optional
+	Toppings []PizzaOrderTopping `json:"toppings,omitempty"`
+
+	// RemoveToppings lists the codes of default toppings that should be
+	// taken out of the product.
+	//
+	// +optional
+	RemoveToppings []string `json:"removeToppings,omitempty"`
+}
+
+type PizzaOrderTopping struct {
+	Code string `json:"code"`
+
+	// Coverage is the part of the pizza that the topping should go on.
+	//
+	// +kubebuilder:validation:Enum=Whole;Left;Right
+	// +kubebuilder:default=Whole
+	// +optional
+	Coverage string `json:"coverage,omitempty"`
+
+	// +kubebuilder:validation:Enum=Light;Normal;Extra;Double
+	// +kubebuilder:default=Normal
+	// +optional
+	Amount string `json:"amount,omitempty"`
 }
 
 type PizzaOrderStatus struct {
-	OrderID    string             `json:"orderID,omitempty"`
+	OrderID string `json:"orderID,omitempty"`
+
+	// OrderKey is the key that the order is placed with, letting Domino's
+	// tell attempts at placing the same order apart from new orders.
+	//
+	// +optional
+	OrderKey string `json:"orderKey,omitempty"`
+
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
-	Price      string             `json:"price,omitempty"`
+
+	// Price is the breakdown of what the order got priced at.
+	//
+	// +optional
+	Price *PizzaOrderPrice `json:"price,omitempty"`
+
+	// ObservedGeneration is the generation of the spec that the status
+	// was last updated for.
+	//
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// PricedFingerprint identifies the contents of the spec (and the
+	// address of the customer) that the order got priced for, so that the
+	// order gets priced again (rather than placed for a stale price) when
+	// they change.
+	//
+	// +optional
+	PricedFingerprint string `json:"pricedFingerprint,omitempty"`
+
+	// EstimatedWaitMinutes is how long Domino's expected the order to
+	// take when it got placed (e.g., "15-25").
+	//
+	// +optional
+	EstimatedWaitMinutes string `json:"estimatedWaitMinutes,omitempty"`
+
+	// Stage is the latest stage (Making, Oven, QualityCheck,
+	// OutForDelivery, or Complete) that the order reached once placed,
+	// according to Domino's order tracker. Each stage reached is also
+	// reflected in a condition of the same type.
+	//
+	// +optional
+	Stage string `json:"stage,omitempty"`
+
+	// Payment describes how the order has been paid for, once placed.
+	//
+	// +optional
+	Payment *PizzaOrderPayment `json:"payment,omitempty"`
+}
+
+// PizzaOrderPrice breaks down the price of an order. Amounts are decimal
+// strings with two decimal places (e.g., "15.81"), in Currency.
+type PizzaOrderPrice struct {
+	// Subtotal is the price of the products, as listed in the menu.
+	Subtotal string `json:"subtotal"`
+
+	// Discounts is how much got taken off of the subtotal.
+	//
+	// +optional
+	Discounts string `json:"discounts,omitempty"`
+
+	// Fees is what's charged on top of the products, e.g., delivery fees
+	// and surcharges.
+	//
+	// +optional
+	Fees string `json:"fees,omitempty"`
+
+	Tax string `json:"tax"`
+
+	// Total is what gets paid for the order, taxes and fees included.
+	Total string `json:"total"`
+
+	// Currency is the ISO 4217 code of the currency of the amounts (e.g.,
+	// "CAD").
+	//
+	// +optional
+	Currency string `json:"currency,omitempty"`
+}
+
+type PizzaOrderPayment struct {
+	Type string `json:"type"`
+
+	// +optional
+	CardType string `json:"cardType,omitempty"`
+
+	// CardLastDigits are the last digits of the number of the card used
+	// to pay for the order, if paid online.
+	//
+	// +optional
+	CardLastDigits string `json:"cardLastDigits,omitempty"`
 }
 
 // +kubebuilder:object:root=true
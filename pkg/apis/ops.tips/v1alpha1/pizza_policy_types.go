@@ -0,0 +1,39 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Max Order Price",type=string,JSONPath=`.spec.maxOrderPrice`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// PizzaPolicy sets defaults and limits for the orders in its namespace.
+type PizzaPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec PizzaPolicySpec `json:"spec,omitempty"`
+}
+
+type PizzaPolicySpec struct {
+	// MaxOrderPrice is the maximum price that orders in the namespace can
+	// be placed for. When more than one policy sets it, the lowest one
+	// applies.
+	//
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	MaxOrderPrice string `json:"maxOrderPrice,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+type PizzaPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PizzaPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&PizzaPolicy{}, &PizzaPolicyList{})
+}
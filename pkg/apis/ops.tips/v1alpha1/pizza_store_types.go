@@ -16,10 +16,31 @@ type PizzaStore struct {
 }
 
 type PizzaStoreSpec struct {
-	ID       string              `json:"id"`
-	Phone    string              `json:"phone"`
-	Address  string              `json:"address"`
+	ID      string `json:"id"`
+	Phone   string `json:"phone"`
+	Address string `json:"address"`
+
+	// ServiceMethods are the service methods (Delivery, Carryout, and
+	// DriveUpCarryout) that the store takes orders for.
+	//
+	// +optional
+	ServiceMethods []string `json:"serviceMethods,omitempty"`
+
+	// PaymentTypes are the payment types (Cash, DoorCredit, CreditCard,
+	// GiftCard, etc) that the store accepts.
+	//
+	// +optional
+	PaymentTypes []string `json:"paymentTypes,omitempty"`
+
+	// Products are the pre-configured products (combos) available in the
+	// store.
 	Products []PizzaStoreProduct `json:"products"`
+
+	// Coupons are the coupons offered by the store, which orders can
+	// apply (see PizzaOrder's `spec.coupons`).
+	//
+	// +optional
+	Coupons []PizzaStoreCoupon `json:"coupons,omitempty"`
 }
 
 type PizzaStoreProduct struct {
@@ -29,7 +50,69 @@ type PizzaStoreProduct struct {
 	Size        string `json:"size"`
 }
 
+type PizzaStoreCoupon struct {
+	Code        string `json:"code"`
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Price is the price of the deal (or the discount), as listed in the
+	// menu.
+	//
+	// +optional
+	Price string `json:"price,omitempty"`
+}
+
+// PizzaStoreMenu is an overview of the full menu of a store: what can be
+// ordered, and for how much. Descriptions, categories and which toppings go
+// on which products are left out to keep PizzaStore objects small (see
+// `pizzactl menu` for those).
+type PizzaStoreMenu struct {
+	// Products are the entries of the menu (e.g., "Hand Tossed Pizza").
+	//
+	// +optional
+	Products []PizzaStoreMenuProduct `json:"products,omitempty"`
+
+	// Variants are the orderable forms of products, like a specific size
+	// of a pizza, along with their prices.
+	//
+	// +optional
+	Variants []PizzaStoreMenuVariant `json:"variants,omitempty"`
+
+	// +optional
+	Toppings []PizzaStoreMenuOption `json:"toppings,omitempty"`
+
+	// +optional
+	Sides []PizzaStoreMenuOption `json:"sides,omitempty"`
+}
+
+type PizzaStoreMenuProduct struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+
+	// Variants lists the IDs of the variants of this product.
+	//
+	// +optional
+	Variants []string `json:"variants,omitempty"`
+}
+
+type PizzaStoreMenuVariant struct {
+	ID        string `json:"id"`
+	Name      string `json:"name,omitempty"`
+	ProductID string `json:"productID,omitempty"`
+	Price     string `json:"price,omitempty"`
+}
+
+type PizzaStoreMenuOption struct {
+	Code string `json:"code"`
+	Name string `json:"name,omitempty"`
+}
+
 type PizzaStoreStatus struct {
+	// Menu is an overview of the full menu of the store, including the
+	// products that can be customized.
+	//
+	// +optional
+	Menu *PizzaStoreMenu `json:"menu,omitempty"`
 }
 
 // +kubebuilder:object:root=true
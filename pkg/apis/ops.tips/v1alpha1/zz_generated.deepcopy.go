@@ -5,10 +5,111 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaBudget) DeepCopyInto(out *PizzaBudget) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaBudget.
+func (in *PizzaBudget) DeepCopy() *PizzaBudget {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaBudget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaBudget) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaBudgetList) DeepCopyInto(out *PizzaBudgetList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PizzaBudget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaBudgetList.
+func (in *PizzaBudgetList) DeepCopy() *PizzaBudgetList {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaBudgetList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaBudgetList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaBudgetSpec) DeepCopyInto(out *PizzaBudgetSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaBudgetSpec.
+func (in *PizzaBudgetSpec) DeepCopy() *PizzaBudgetSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaBudgetSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaBudgetStatus) DeepCopyInto(out *PizzaBudgetStatus) {
+	*out = *in
+	if in.PeriodStart != nil {
+		in, out := &in.PeriodStart, &out.PeriodStart
+		*out = (*in).DeepCopy()
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaBudgetStatus.
+func (in *PizzaBudgetStatus) DeepCopy() *PizzaBudgetStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaBudgetStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaCustomer) DeepCopyInto(out *PizzaCustomer) {
 	*out = *in
@@ -72,6 +173,7 @@ func (in *PizzaCustomerList) DeepCopyObject() runtime.Object {
 func (in *PizzaCustomerSpec) DeepCopyInto(out *PizzaCustomerSpec) {
 	*out = *in
 	out.CreditCardSecretRef = in.CreditCardSecretRef
+	out.GiftCardSecretRef = in.GiftCardSecretRef
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaCustomerSpec.
@@ -88,6 +190,11 @@ func (in *PizzaCustomerSpec) DeepCopy() *PizzaCustomerSpec {
 func (in *PizzaCustomerStatus) DeepCopyInto(out *PizzaCustomerStatus) {
 	*out = *in
 	out.ClosestStoreRef = in.ClosestStoreRef
+	if in.StoreRefs != nil {
+		in, out := &in.StoreRefs, &out.StoreRefs
+		*out = make([]corev1.LocalObjectReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -166,9 +273,49 @@ func (in *PizzaOrderList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaOrderPayment) DeepCopyInto(out *PizzaOrderPayment) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaOrderPayment.
+func (in *PizzaOrderPayment) DeepCopy() *PizzaOrderPayment {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaOrderPayment)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaOrderPrice) DeepCopyInto(out *PizzaOrderPrice) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaOrderPrice.
+func (in *PizzaOrderPrice) DeepCopy() *PizzaOrderPrice {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaOrderPrice)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaOrderProduct) DeepCopyInto(out *PizzaOrderProduct) {
 	*out = *in
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaOrderTopping, len(*in))
+		copy(*out, *in)
+	}
+	if in.RemoveToppings != nil {
+		in, out := &in.RemoveToppings, &out.RemoveToppings
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaOrderProduct.
@@ -189,6 +336,13 @@ func (in *PizzaOrderSpec) DeepCopyInto(out *PizzaOrderSpec) {
 	if in.Products != nil {
 		in, out := &in.Products, &out.Products
 		*out = make([]PizzaOrderProduct, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Coupons != nil {
+		in, out := &in.Coupons, &out.Coupons
+		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
 }
@@ -213,6 +367,16 @@ func (in *PizzaOrderStatus) DeepCopyInto(out *PizzaOrderStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Price != nil {
+		in, out := &in.Price, &out.Price
+		*out = new(PizzaOrderPrice)
+		**out = **in
+	}
+	if in.Payment != nil {
+		in, out := &in.Payment, &out.Payment
+		*out = new(PizzaOrderPayment)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaOrderStatus.
@@ -225,13 +389,101 @@ func (in *PizzaOrderStatus) DeepCopy() *PizzaOrderStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaOrderTopping) DeepCopyInto(out *PizzaOrderTopping) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaOrderTopping.
+func (in *PizzaOrderTopping) DeepCopy() *PizzaOrderTopping {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaOrderTopping)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaPolicy) DeepCopyInto(out *PizzaPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaPolicy.
+func (in *PizzaPolicy) DeepCopy() *PizzaPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaPolicyList) DeepCopyInto(out *PizzaPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]PizzaPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaPolicyList.
+func (in *PizzaPolicyList) DeepCopy() *PizzaPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PizzaPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaPolicySpec) DeepCopyInto(out *PizzaPolicySpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaPolicySpec.
+func (in *PizzaPolicySpec) DeepCopy() *PizzaPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStore) DeepCopyInto(out *PizzaStore) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStore.
@@ -252,6 +504,21 @@ func (in *PizzaStore) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStoreCoupon) DeepCopyInto(out *PizzaStoreCoupon) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreCoupon.
+func (in *PizzaStoreCoupon) DeepCopy() *PizzaStoreCoupon {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStoreCoupon)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStoreList) DeepCopyInto(out *PizzaStoreList) {
 	*out = *in
@@ -284,6 +551,93 @@ func (in *PizzaStoreList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStoreMenu) DeepCopyInto(out *PizzaStoreMenu) {
+	*out = *in
+	if in.Products != nil {
+		in, out := &in.Products, &out.Products
+		*out = make([]PizzaStoreMenuProduct, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]PizzaStoreMenuVariant, len(*in))
+		copy(*out, *in)
+	}
+	if in.Toppings != nil {
+		in, out := &in.Toppings, &out.Toppings
+		*out = make([]PizzaStoreMenuOption, len(*in))
+		copy(*out, *in)
+	}
+	if in.Sides != nil {
+		in, out := &in.Sides, &out.Sides
+		*out = make([]PizzaStoreMenuOption, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreMenu.
+func (in *PizzaStoreMenu) DeepCopy() *PizzaStoreMenu {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStoreMenu)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStoreMenuOption) DeepCopyInto(out *PizzaStoreMenuOption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreMenuOption.
+func (in *PizzaStoreMenuOption) DeepCopy() *PizzaStoreMenuOption {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStoreMenuOption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStoreMenuProduct) DeepCopyInto(out *PizzaStoreMenuProduct) {
+	*out = *in
+	if in.Variants != nil {
+		in, out := &in.Variants, &out.Variants
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreMenuProduct.
+func (in *PizzaStoreMenuProduct) DeepCopy() *PizzaStoreMenuProduct {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStoreMenuProduct)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PizzaStoreMenuVariant) DeepCopyInto(out *PizzaStoreMenuVariant) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreMenuVariant.
+func (in *PizzaStoreMenuVariant) DeepCopy() *PizzaStoreMenuVariant {
+	if in == nil {
+		return nil
+	}
+	out := new(PizzaStoreMenuVariant)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStoreProduct) DeepCopyInto(out *PizzaStoreProduct) {
 	*out = *in
@@ -302,11 +656,26 @@ func (in *PizzaStoreProduct) DeepCopy() *PizzaStoreProduct {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStoreSpec) DeepCopyInto(out *PizzaStoreSpec) {
 	*out = *in
+	if in.ServiceMethods != nil {
+		in, out := &in.ServiceMethods, &out.ServiceMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PaymentTypes != nil {
+		in, out := &in.PaymentTypes, &out.PaymentTypes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.Products != nil {
 		in, out := &in.Products, &out.Products
 		*out = make([]PizzaStoreProduct, len(*in))
 		copy(*out, *in)
 	}
+	if in.Coupons != nil {
+		in, out := &in.Coupons, &out.Coupons
+		*out = make([]PizzaStoreCoupon, len(*in))
+		copy(*out, *in)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreSpec.
@@ -322,6 +691,11 @@ func (in *PizzaStoreSpec) DeepCopy() *PizzaStoreSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PizzaStoreStatus) DeepCopyInto(out *PizzaStoreStatus) {
 	*out = *in
+	if in.Menu != nil {
+		in, out := &in.Menu, &out.Menu
+		*out = new(PizzaStoreMenu)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PizzaStoreStatus.
@@ -4,11 +4,19 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"encoding/xml"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"strings"
 	"time"
 
+	"github.com/go-logr/logr"
+	"golang.org/x/time/rate"
+
 	"github.com/cirocosta/pizza-controller/pkg/dominos/internal/api"
 )
 
@@ -22,131 +30,457 @@ const (
 	PathStoreMenu    = "/power/store/%s/menu"
 )
 
+var (
+	canadianPostalCode  = regexp.MustCompile(`^[A-Za-z]\d[A-Za-z][ -]?\d[A-Za-z]\d$`)
+	unitedStatesZipCode = regexp.MustCompile(`^\d{5}(-\d{4})?$`)
+
+	// quebecPostalCode matches the postal codes of Quebec (the only ones
+	// starting with G, H or J) anywhere in an address.
+	quebecPostalCode = regexp.MustCompile(`\b[GHJghj]\d[A-Za-z][ -]?\d[A-Za-z]\d\b`)
+)
+
+// URLForCountry retrieves the base URL of the API serving customers from a
+// given country.
+func URLForCountry(country Country) (string, error) {
+	switch country {
+	case CountryCanada:
+		return CanadaURL, nil
+	case CountryUnitedStates:
+		return UnitedStatesURL, nil
+	}
+
+	return "", fmt.Errorf("unsupported country '%s'", country)
+}
+
+// countryForURL determines the country served by one of the per-country API
+// base URLs, if it's one of them.
+func countryForURL(host string) Country {
+	switch strings.TrimSuffix(host, "/") {
+	case CanadaURL:
+		return CountryCanada
+	case UnitedStatesURL:
+		return CountryUnitedStates
+	}
+
+	return ""
+}
+
+// CountryForAddress derives the country an address is in from the format of
+// its zip (or postal) code, reporting false when that can't be told.
+func CountryForAddress(addr Address) (Country, bool) {
+	zip := strings.TrimSpace(addr.Zip)
+
+	switch {
+	case canadianPostalCode.MatchString(zip):
+		return CountryCanada, true
+	case unitedStatesZipCode.MatchString(zip):
+		return CountryUnitedStates, true
+	}
+
+	return "", false
+}
+
+// LanguageForStore determines the language that the menu of a store is
+// best retrieved in, from its address: French for stores in Quebec, English
+// for all others. Being a property of the store, it's the same regardless of
+// who's nearby it.
+func LanguageForStore(address string) string {
+	if quebecPostalCode.MatchString(address) {
+		return "fr"
+	}
+
+	return DefaultLanguage
+}
+
+// DefaultRequestTimeout is how long a request to Domino's can take before
+// it's given up on, unless configured otherwise.
+const DefaultRequestTimeout = 15 * time.Second
+
 type Client struct {
-	host   *url.URL
-	client *http.Client
+	host           *url.URL
+	trackerHost    *url.URL
+	client         *http.Client
+	requestTimeout time.Duration
+	retryPolicy    RetryPolicy
+	limiter        *rate.Limiter
+	country        Country
+	transport      http.RoundTripper
+	userAgent      string
+	language       string
+	log            logr.Logger
+	debug          bool
+	harDir         string
+	metrics        *Metrics
+	recordDir      string
+	replayDir      string
+}
+
+// DefaultLanguage is the language that menus are retrieved in (and that
+// orders are placed in), unless configured otherwise.
+const DefaultLanguage = "en"
+
+// Option customizes a Client.
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client that requests are made with (a copy
+// of it, that is, so that the transports set up by the other options don't
+// leak into it).
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Client) {
+		hc := *client
+		c.client = &hc
+	}
+}
+
+// WithTransport sets the transport that requests go out through, taking
+// precedence over the one of the HTTP client (see WithHTTPClient).
+func WithTransport(transport http.RoundTripper) Option {
+	return func(c *Client) {
+		c.transport = transport
+	}
+}
+
+// WithUserAgent sets the User-Agent header sent along with every request.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.userAgent = userAgent
+	}
+}
+
+// WithLanguage sets the language (e.g., "fr") that menus are retrieved in,
+// and that orders are placed in.
+func WithLanguage(language string) Option {
+	return func(c *Client) {
+		c.language = language
+	}
+}
+
+// WithRequestTimeout sets how long each request can take before it's given
+// up on (a timeout of zero meaning no timeout other than the one set in the
+// context passed to the call, if any).
+func WithRequestTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		c.requestTimeout = timeout
+	}
+}
+
+// WithRetryPolicy sets the policy for retrying requests that are safe to be
+// repeated (store locator, menu, pricing, and tracking - never placing
+// orders).
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(c *Client) {
+		c.retryPolicy = policy
+	}
+}
+
+// WithRateLimiter makes every request (retries included) wait for the
+// limiter to allow it before going out.
+//
+// The same limiter can be shared by several clients so that, together,
+// they don't go over a certain rate.
+func WithRateLimiter(limiter *rate.Limiter) Option {
+	return func(c *Client) {
+		c.limiter = limiter
+	}
+}
+
+// WithMetrics has the requests made by the client reported to a set of
+// Metrics.
+func WithMetrics(metrics *Metrics) Option {
+	return func(c *Client) {
+		c.metrics = metrics
+	}
+}
+
+// WithCountry sets the country of the customers the client serves, which
+// determines the currency that orders get priced in.
+//
+// It's only needed when the host isn't one of the per-country ones (see
+// URLForCountry).
+func WithCountry(country Country) Option {
+	return func(c *Client) {
+		c.country = country
+	}
+}
+
+// WithLogger sets the logger that the client logs through (see WithDebug).
+func WithLogger(log logr.Logger) Option {
+	return func(c *Client) {
+		c.log = log
+	}
+}
+
+// WithDebug has the requests made and the responses to them logged at
+// DebugVerbosity (with personal information and payment details redacted).
+func WithDebug() Option {
+	return func(c *Client) {
+		c.debug = true
+	}
+}
+
+// WithHARDirectory has, when debugging, every request made and the response
+// to it captured as a (redacted) HAR file in a directory.
+func WithHARDirectory(dir string) Option {
+	return func(c *Client) {
+		c.harDir = dir
+	}
 }
 
-func NewClient(host string, debug bool) (*Client, error) {
+// WithRecording has every exchange with Domino's recorded to a directory,
+// as a (redacted) Fixture, so it can be replayed later on (see WithReplay).
+func WithRecording(dir string) Option {
+	return func(c *Client) {
+		c.recordDir = dir
+	}
+}
+
+// WithReplay has requests served from the fixtures previously recorded to
+// a directory (see WithRecording), rather than by Domino's.
+func WithReplay(dir string) Option {
+	return func(c *Client) {
+		c.replayDir = dir
+	}
+}
+
+// NewClient instantiates a client of the API served at `host` (see
+// URLForCountry), customized by a set of options.
+func NewClient(host string, opts ...Option) (*Client, error) {
 	h, err := url.Parse(host)
 	if err != nil {
 		return nil, fmt.Errorf("url parse '%s': %w", host, err)
 	}
 
-	var transport http.RoundTripper = &DumpTransport{r: http.DefaultTransport}
-	if !debug {
+	tracker, err := trackerURL(h)
+	if err != nil {
+		return nil, fmt.Errorf("tracker url: %w", err)
+	}
+
+	c := &Client{
+		host:           h,
+		trackerHost:    tracker,
+		client:         &http.Client{},
+		requestTimeout: DefaultRequestTimeout,
+		retryPolicy:    DefaultRetryPolicy,
+		country:        countryForURL(host),
+		language:       DefaultLanguage,
+		log:            nopLogger{},
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.client.Transport = c.roundTripper()
+
+	return c, nil
+}
+
+// roundTripper assembles the chain of transports that requests go through,
+// from the outermost (debugging) to the innermost (the one actually
+// reaching out to Domino's, or replaying what it once replied).
+func (c *Client) roundTripper() http.RoundTripper {
+	var transport http.RoundTripper
+
+	switch {
+	case c.transport != nil:
+		transport = c.transport
+	case c.client.Transport != nil:
+		transport = c.client.Transport
+	default:
 		transport = http.DefaultTransport
 	}
 
-	httpClient := &http.Client{
-		Timeout:   15 * time.Second,
-		Transport: transport,
+	switch {
+	case c.replayDir != "":
+		transport = &ReplayTransport{dir: c.replayDir}
+	case c.recordDir != "":
+		transport = &RecordTransport{r: transport, dir: c.recordDir}
 	}
 
-	return &Client{
-		host:   h,
-		client: httpClient,
-	}, nil
+	if c.metrics != nil {
+		transport = &MetricsTransport{r: transport, metrics: c.metrics}
+	}
+
+	if c.debug {
+		transport = &DebugTransport{
+			r:      transport,
+			log:    c.log,
+			harDir: c.harDir,
+		}
+	}
+
+	return transport
 }
 
-func (c *Client) PlaceOrder(ctx context.Context, order Order) (string, error) {
+// PlacedOrder is an order that Domino's accepted.
+type PlacedOrder struct {
+	ID string
+
+	// EstimatedWaitMinutes is how long Domino's expects the order to take
+	// (e.g., "15-25").
+	EstimatedWaitMinutes string
+}
+
+func (c *Client) PlaceOrder(ctx context.Context, order Order) (*PlacedOrder, error) {
+	if err := order.Validate(); err != nil {
+		return nil, &notSentError{fmt.Errorf("validate: %w", err)}
+	}
+
 	url := *c.host
 	url.Path = PathPlaceOrder
 
+	// placing an order is not idempotent: if the request went through
+	// but the response didn't make it back, retrying would place it
+	// again.
+	//
 	msg := c.orderMessage(order)
-	buf := &bytes.Buffer{}
-	if err := json.NewEncoder(buf).Encode(&msg); err != nil {
-		return "", fmt.Errorf("encode order: %w", err)
+	body := api.PlaceOrderResponse{}
+	if err := c.doOnce(ctx, http.MethodPost, &url, &msg, &body); err != nil {
+		return nil, err
 	}
 
-	resp, err := c.client.Post(url.String(), "application/json", buf)
-	if err != nil {
-		return "", fmt.Errorf("post %s: %w", url, err)
+	return &PlacedOrder{
+		ID:                   body.Order.OrderID,
+		EstimatedWaitMinutes: body.Order.EstimatedWaitMinutes,
+	}, nil
+}
+
+// PriceOrder retrieves how much an order would cost if placed.
+func (c *Client) PriceOrder(ctx context.Context, order Order) (*Price, error) {
+	// pricing doesn't involve paying, so there's no need to send (or
+	// even have) the payment details.
+	//
+	order.PaymentType = ""
+	order.CreditCard = CreditCard{}
+	order.GiftCard = GiftCard{}
+
+	if err := order.Validate(); err != nil {
+		return nil, fmt.Errorf("validate: %w", err)
 	}
-	defer resp.Body.Close()
 
-	body := api.PlaceOrderResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
+	url := *c.host
+	url.Path = PathPriceOrder
+
+	msg := c.orderMessage(order)
+	body := api.PriceResponse{}
+	if err := c.do(ctx, http.MethodPost, &url, &msg, &body); err != nil {
+		return nil, err
 	}
 
-	if body.Status == -1 {
-		return "", fmt.Errorf("status -1: %s", body.Order.StatusItems.String())
+	price, err := newPrice(body.Order.Amounts)
+	if err != nil {
+		return nil, fmt.Errorf("price: %w", err)
 	}
 
-	return body.Order.OrderID, nil
+	price.Currency, _ = CurrencyForCountry(c.country)
+	price.Coupons = newCouponStatuses(body.Order.Coupons)
+
+	return price, nil
 }
 
-func (c *Client) PriceOrder(ctx context.Context, order Order) (string, error) {
-	url := *c.host
-	url.Path = PathPriceOrder
+// newPrice converts the amounts that Domino's priced an order at into a
+// Price.
+func newPrice(amounts api.Amounts) (*Price, error) {
+	price := &Price{}
+	var surcharge, bottle Money
+
+	for _, field := range []struct {
+		name   string
+		amount json.Number
+		dest   *Money
+	}{
+		{"Menu", amounts.Menu, &price.Subtotal},
+		{"Discount", amounts.Discount, &price.Discounts},
+		{"Surcharge", amounts.Surcharge, &surcharge},
+		{"Bottle", amounts.Bottle, &bottle},
+		{"Tax", amounts.Tax, &price.Tax},
+		{"Customer", amounts.Customer, &price.Total},
+	} {
+		if field.amount == "" {
+			continue
+		}
 
-	order.CreditCard = CreditCard{}
+		amount, err := ParseMoney(field.amount.String())
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", strings.ToLower(field.name), err)
+		}
 
-	msg := c.orderMessage(order)
-	buf := &bytes.Buffer{}
-	if err := json.NewEncoder(buf).Encode(&msg); err != nil {
-		return "", fmt.Errorf("encode order: %w", err)
+		*field.dest = amount
 	}
 
-	resp, err := c.client.Post(url.String(), "application/json", buf)
+	price.Fees = surcharge + bottle
+
+	return price, nil
+}
+
+// StoreMenu retrieves the full menu of a store, in the language of the
+// client (see WithLanguage).
+func (c *Client) StoreMenu(ctx context.Context, storeID string) (*Menu, error) {
+	return c.StoreMenuIn(ctx, storeID, c.language)
+}
+
+// StoreMenuIn retrieves the full menu of a store in a given language (e.g.,
+// the one of the store, see Store.Language).
+func (c *Client) StoreMenuIn(ctx context.Context, storeID, language string) (*Menu, error) {
+	body, err := c.storeMenu(ctx, storeID, language)
 	if err != nil {
-		return "", fmt.Errorf("post %s: %w", url, err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body := api.PriceResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return "", fmt.Errorf("decode response: %w", err)
-	}
+	return newMenu(body), nil
+}
 
-	if body.Status == -1 {
-		return "", fmt.Errorf("status -1: %s", body.Order.CorrectiveAction.Code)
+// ValidateProducts checks that the products (and the customizations made to
+// them) can be ordered from a store, according to its menu.
+func (c *Client) ValidateProducts(ctx context.Context, storeID string, products []Product) error {
+	menu, err := c.StoreMenu(ctx, storeID)
+	if err != nil {
+		return err
 	}
 
-	return fmt.Sprintf("%f", body.Order.Amounts.Customer), nil
+	return menu.ValidateProducts(products)
 }
 
-func (c *Client) StoreMenu(ctx context.Context, storeID string) ([]*Product, error) {
+func (c *Client) storeMenu(ctx context.Context, storeID, language string) (*api.MenuResponse, error) {
 	url := *c.host
 	url.Path = fmt.Sprintf(PathStoreMenu, storeID)
 
 	v := url.Query()
-	v.Set("lang", "en")
+	v.Set("lang", language)
 	v.Set("structured", "true")
 
 	url.RawQuery = v.Encode()
 
-	resp, err := c.client.Get(url.String())
-	if err != nil {
-		return nil, fmt.Errorf("get '%s': %w", url, err)
+	body := &api.MenuResponse{}
+	if err := c.do(ctx, http.MethodGet, &url, nil, body); err != nil {
+		return nil, err
 	}
 
-	defer resp.Body.Close()
-
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("get status code: %d", resp.StatusCode)
-	}
+	return body, nil
+}
 
-	body := api.MenuResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+// StoresNearby retrieves the stores close to an address that are open for a
+// service method, closest first.
+func (c *Client) StoresNearby(ctx context.Context, addr Address, service Service) ([]*Store, error) {
+	located, err := c.LocateStores(ctx, addr, service)
+	if err != nil {
+		return nil, err
 	}
 
-	res := []*Product{}
-	for _, product := range body.Preconfigured {
-		res = append(res, &Product{
-			ID:          product.Code,
-			Description: product.Description,
-			Name:        product.Name,
-			Size:        product.Size,
-		})
+	stores := []*Store{}
+	for _, store := range located {
+		if store.Open {
+			stores = append(stores, store)
+		}
 	}
 
-	return res, nil
+	return stores, nil
 }
 
-func (c *Client) StoresNearby(ctx context.Context, addr Address, service Service) ([]*Store, error) {
+// LocateStores retrieves all of the stores close to an address that serve
+// it, closest first, regardless of whether they're open for a service method
+// right now (see Store.Open).
+func (c *Client) LocateStores(ctx context.Context, addr Address, service Service) ([]*Store, error) {
 	url := *c.host
 	url.Path = PathStoreLocator
 
@@ -157,44 +491,226 @@ func (c *Client) StoresNearby(ctx context.Context, addr Address, service Service
 
 	url.RawQuery = v.Encode()
 
-	resp, err := c.client.Get(url.String())
+	body := api.StoreLocatorResponse{}
+	if err := c.do(ctx, http.MethodGet, &url, nil, &body); err != nil {
+		return nil, err
+	}
+
+	stores := []*Store{}
+	for _, store := range body.Stores {
+		open := store.IsOpen
+		switch service {
+		case ServiceCarryout:
+			open = open && store.ServiceIsOpen.Carryout
+		case ServiceDelivery:
+			open = open && store.ServiceIsOpen.Delivery
+		case ServiceDriveUpCarryout:
+			open = open && store.ServiceIsOpen.DriveUpCarryout
+		}
+
+		services := []Service{}
+		if store.AllowDeliveryOrders {
+			services = append(services, ServiceDelivery)
+		}
+		if store.AllowCarryoutOrders {
+			services = append(services, ServiceCarryout)
+		}
+		if store.AllowDuc {
+			services = append(services, ServiceDriveUpCarryout)
+		}
+
+		paymentTypes := []PaymentType{}
+		for _, paymentType := range store.AcceptablePaymentTypes {
+			paymentTypes = append(paymentTypes, PaymentType(paymentType))
+		}
+
+		stores = append(stores, &Store{
+			ID:           store.StoreID,
+			Phone:        store.Phone,
+			Address:      store.AddressDescription,
+			Services:     services,
+			PaymentTypes: paymentTypes,
+			Language:     LanguageForStore(store.AddressDescription),
+			Open:         open,
+		})
+	}
+
+	return stores, nil
+}
+
+// do performs a request against Domino's (see doOnce), retrying it
+// according to the client's retry policy.
+//
+// Only requests that are safe to be repeated should go through it.
+func (c *Client) do(ctx context.Context, method string, url *url.URL, in, out interface{}) error {
+	for attempt := 1; ; attempt++ {
+		err := c.doOnce(ctx, method, url, in, out)
+		if err == nil || !IsRetryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		delay, retry := c.retryPolicy.Backoff(attempt, err)
+		if !retry {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// doOnce performs a request against Domino's (see exchange), reporting it to
+// the client's metrics (if any) when it fails.
+//
+// Failures get counted here rather than by the MetricsTransport, as Domino's
+// rejects some requests with a successful status code, which only decoding
+// the response tells apart.
+func (c *Client) doOnce(ctx context.Context, method string, url *url.URL, in, out interface{}) error {
+	err := c.exchange(ctx, method, url, in, out)
+	if err != nil && c.metrics != nil {
+		c.metrics.RequestErrors.WithLabelValues(endpointName(url.Path), errorCode(err)).Inc()
+	}
+
+	return err
+}
+
+// exchange performs a request against Domino's, encoding `in` (if any) as
+// the request body, and decoding the response body into `out`.
+//
+// The request is bound to `ctx`, and given up on once the client's request
+// timeout elapses.
+func (c *Client) exchange(ctx context.Context, method string, url *url.URL, in, out interface{}) error {
+	if c.limiter != nil {
+		if err := c.limiter.Wait(ctx); err != nil {
+			return &notSentError{fmt.Errorf("rate limiter wait: %w", err)}
+		}
+	}
+
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+
+	var reqBody io.Reader
+	if in != nil {
+		buf := &bytes.Buffer{}
+		if err := json.NewEncoder(buf).Encode(in); err != nil {
+			return &notSentError{fmt.Errorf("encode request: %w", err)}
+		}
+
+		reqBody = buf
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url.String(), reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("get '%s': %w", url, err)
+		return &notSentError{fmt.Errorf("new request: %w", err)}
 	}
 
-	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("get status code: %d", resp.StatusCode)
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		if isDialError(err) {
+			return &notSentError{fmt.Errorf("do request: %w", err)}
+		}
+
+		return fmt.Errorf("do request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
-	body := api.StoreLocatorResponse{}
-	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
-		return nil, fmt.Errorf("decode response: %w", err)
+	respBody, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
 	}
 
-	stores := []*Store{}
-	for _, store := range body.Stores {
-		if !store.IsOpen {
-			continue
-		}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%s '%s': %w", strings.ToLower(method), url.String(),
+			newAPIError(resp.StatusCode, respBody),
+		)
+	}
 
-		if service == ServiceCarryout && !store.ServiceIsOpen.Carryout {
-			continue
+	if x, ok := out.(xmlResponse); ok {
+		if err := xml.Unmarshal(respBody, x.v); err != nil {
+			return fmt.Errorf("decode response: %w", err)
 		}
 
-		if service == ServiceDelivery && !store.ServiceIsOpen.Delivery {
-			continue
+		return nil
+	}
+
+	status := api.Status{}
+	if err := json.Unmarshal(respBody, &status); err == nil && status.Status == -1 {
+		return newAPIError(resp.StatusCode, respBody)
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	return nil
+}
+
+// xmlResponse wraps where to decode the responses of the (few) endpoints
+// that reply with XML rather than JSON into.
+type xmlResponse struct {
+	v interface{}
+}
+
+// newAPIError assembles the error for a request that Domino's refused to
+// fulfill, based on the body of the response (if it's one that can be made
+// sense of).
+func newAPIError(statusCode int, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: statusCode,
+		Body:       body,
+	}
+
+	status := api.Status{}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return apiErr
+	}
+
+	items := append(api.StatusItems{}, status.Order.StatusItems...)
+	items = append(items, status.StatusItems...)
+	for _, item := range items {
+		message := item.Message
+		if message == "" {
+			message = item.PulseText
 		}
 
-		stores = append(stores, &Store{
-			ID:      store.StoreID,
-			Phone:   store.Phone,
-			Address: store.AddressDescription,
+		apiErr.StatusItems = append(apiErr.StatusItems, StatusItem{
+			Code:    item.Code,
+			Message: message,
 		})
 	}
 
-	return stores, nil
+	for _, coupon := range newCouponStatuses(status.Order.Coupons) {
+		apiErr.StatusItems = append(apiErr.StatusItems, coupon.Problems...)
+	}
+
+	action := status.Order.CorrectiveAction
+	if action.Code != "" || action.Action != "" || action.Detail != "" {
+		apiErr.CorrectiveAction = &CorrectiveAction{
+			Action: action.Action,
+			Code:   action.Code,
+			Detail: action.Detail,
+		}
+	}
+
+	return apiErr
 }
 
 func (c *Client) orderMessage(order Order) api.OrderMessage {
@@ -209,9 +725,11 @@ func (c *Client) orderMessage(order Order) api.OrderMessage {
 				Zipcode:      order.Address.Zip,
 				AddrType:     api.AddressTypeHouse,
 			},
-			LanguageCode:  "en",
+			LanguageCode:  c.language,
+			OrderID:       order.Key,
 			StoreID:       order.StoreID,
 			ServiceMethod: string(order.Service),
+			Coupons:       []*api.OrderCoupon{},
 			Payments:      []*api.OrderPayment{},
 			Products:      []*api.OrderProduct{},
 		},
@@ -224,27 +742,102 @@ func (c *Client) orderMessage(order Order) api.OrderMessage {
 		msg.Order.Phone = order.PersonalInformation.Phone
 	}
 
-	if order.CreditCard.Number != "" {
-		msg.Order.Payments = append(msg.Order.Payments, &api.OrderPayment{
-			Type:     "DoorCredit",
-			CardType: string(order.CreditCard.Type),
-			Amount:   order.Amount,
-			// Number:       order.CreditCard.Number,
-			// Expiration:   order.CreditCard.Expiration,
-			// SecurityCode: order.CreditCard.SecurityCode,
-			// PostalCode:   order.CreditCard.PostalCode,
+	if payment := orderPayment(order); payment != nil {
+		msg.Order.Payments = append(msg.Order.Payments, payment)
+	}
+
+	for idx, code := range order.Coupons {
+		msg.Order.Coupons = append(msg.Order.Coupons, &api.OrderCoupon{
+			Code:  code,
+			Qty:   1,
+			ID:    idx,
+			IsNew: true,
 		})
 	}
 
 	for idx, product := range order.Products {
+		qty := product.Quantity
+		if qty == 0 {
+			qty = 1
+		}
+
 		msg.Order.Products = append(msg.Order.Products, &api.OrderProduct{
 			ID:  idx,
-			Qty: 1,
+			Qty: qty,
 			ItemCommon: api.ItemCommon{
 				Code: product.ID,
 			},
+			Opts: productOptions(product),
 		})
 	}
 
 	return msg
 }
+
+// orderPayment assembles the payment for an order according to its payment
+// type, if any.
+func orderPayment(order Order) *api.OrderPayment {
+	switch order.PaymentType {
+	case PaymentTypeCash:
+		return &api.OrderPayment{
+			Type:   string(PaymentTypeCash),
+			Amount: order.Amount.Float64(),
+		}
+	case PaymentTypeDoorCredit:
+		return &api.OrderPayment{
+			Type:     string(PaymentTypeDoorCredit),
+			CardType: string(order.CreditCard.Type),
+			Amount:   order.Amount.Float64(),
+		}
+	case PaymentTypeCreditCard:
+		return &api.OrderPayment{
+			Type:         string(PaymentTypeCreditCard),
+			CardType:     string(order.CreditCard.Type),
+			Number:       order.CreditCard.Number,
+			Expiration:   order.CreditCard.Expiration,
+			SecurityCode: order.CreditCard.SecurityCode,
+			PostalCode:   order.CreditCard.PostalCode,
+			Amount:       order.Amount.Float64(),
+		}
+	case PaymentTypeGiftCard:
+		return &api.OrderPayment{
+			Type:         string(PaymentTypeGiftCard),
+			Number:       order.GiftCard.Number,
+			SecurityCode: order.GiftCard.PIN,
+			Amount:       order.Amount.Float64(),
+		}
+	}
+
+	return nil
+}
+
+// productOptions assembles the `Options` payload that describes the toppings
+// added to (or removed from) a product, e.g.:
+//
+//	{"P": {"1/2": "1.5"}, "X": {"1/1": "0"}}
+//
+// i.e., extra pepperoni on the left half, and no sauce.
+func productOptions(product Product) map[string]interface{} {
+	if len(product.Toppings) == 0 {
+		return nil
+	}
+
+	opts := map[string]interface{}{}
+	for _, topping := range product.Toppings {
+		coverage := topping.Coverage
+		if coverage == "" {
+			coverage = CoverageWhole
+		}
+
+		amount := topping.Amount
+		if amount == "" {
+			amount = ToppingAmountNormal
+		}
+
+		opts[topping.Code] = map[string]string{
+			string(coverage): string(amount),
+		}
+	}
+
+	return opts
+}
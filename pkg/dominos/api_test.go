@@ -0,0 +1,307 @@
+package dominos_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/dominos/dominostest"
+)
+
+const phone = "416-555-0199"
+
+func newServer(t *testing.T) *dominostest.Server {
+	srv := dominostest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddStore(dominostest.Store{
+		ID:       "10391",
+		Address:  "150 Front St W\nToronto, ON M5J 2N1",
+		Carryout: true,
+		TaxRate:  0.13,
+		Products: []dominostest.Product{
+			{Code: "10SCREEN", Name: "Small Hand Tossed", Price: 9.03},
+		},
+		Coupons: []dominostest.Coupon{
+			{Code: "9193", Discount: 1, Products: []string{"10SCREEN"}},
+		},
+	})
+
+	return srv
+}
+
+// newClient instantiates a client against the fake server, retrying failed
+// requests (up to 3 attempts) right away.
+func newClient(t *testing.T, url string) *dominos.Client {
+	client, err := dominos.NewClient(url,
+		dominos.WithCountry(dominos.CountryCanada),
+		dominos.WithRetryPolicy(dominos.ExponentialBackoff{MaxAttempts: 3}),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	return client
+}
+
+func newOrder() dominos.Order {
+	return dominos.Order{
+		StoreID: "10391",
+		PersonalInformation: dominos.PersonalInformation{
+			FirstName: "barack",
+			LastName:  "obama",
+			Email:     "barack@example.com",
+			Phone:     phone,
+		},
+		Products: []dominos.Product{
+			{ID: "10SCREEN", Quantity: 1},
+		},
+		Service:     dominos.ServiceCarryout,
+		PaymentType: dominos.PaymentTypeCash,
+		Amount:      1020,
+		Key:         "c0ffee",
+	}
+}
+
+func TestPlaceOrderIdempotency(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	client := newClient(t, srv.URL)
+
+	// the order goes through, but the response to placing it doesn't
+	// make it back.
+	//
+	srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+		StatusCode:   http.StatusBadGateway,
+		AfterPlacing: true,
+		Times:        1,
+	})
+
+	_, err := client.PlaceOrder(ctx, newOrder())
+	if err == nil {
+		t.Fatal("expected placing the order to fail")
+	}
+
+	if dominos.IsNotSent(err) {
+		t.Errorf("expected the failure not to be considered as not sent: %v", err)
+	}
+
+	if n := srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Errorf("expected placing the order not to be retried, got %d request(s)", n)
+	}
+
+	orders := srv.Orders()
+	if len(orders) != 1 {
+		t.Fatalf("expected the order to have been placed once, got %d order(s)", len(orders))
+	}
+
+	// the tracker tells that it did go through ...
+	//
+	tracked, found, err := client.TrackOrderByKey(ctx, phone, "c0ffee")
+	if err != nil {
+		t.Fatalf("track order by key: %v", err)
+	}
+
+	if !found || tracked.OrderID != orders[0].ID {
+		t.Errorf("expected the tracker to find order %s, got %+v (found: %v)", orders[0].ID, tracked, found)
+	}
+
+	if _, found, _ := client.TrackOrderByKey(ctx, phone, "unknown"); found {
+		t.Error("expected the tracker not to find an order placed with another key")
+	}
+
+	// ... and placing it again with the same key doesn't place it twice.
+	//
+	placed, err := client.PlaceOrder(ctx, newOrder())
+	if err != nil {
+		t.Fatalf("place order again: %v", err)
+	}
+
+	if placed.ID != orders[0].ID {
+		t.Errorf("expected order %s, got %s", orders[0].ID, placed.ID)
+	}
+
+	if n := len(srv.Orders()); n != 1 {
+		t.Errorf("expected the order to have been placed once, got %d order(s)", n)
+	}
+}
+
+func TestErrorClassification(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("invalid orders are not sent", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		order := newOrder()
+		order.Products = nil
+
+		_, err := client.PlaceOrder(ctx, order)
+		if !dominos.IsNotSent(err) {
+			t.Errorf("expected a not sent error, got %v", err)
+		}
+
+		if n := srv.Requests(dominostest.EndpointPlaceOrder); n != 0 {
+			t.Errorf("expected no requests, got %d", n)
+		}
+	})
+
+	t.Run("unreachable servers are not sent anything", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+		srv.Close()
+
+		_, err := client.PlaceOrder(ctx, newOrder())
+		if !dominos.IsNotSent(err) {
+			t.Errorf("expected a not sent error, got %v", err)
+		}
+	})
+
+	t.Run("server errors are retried", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPriceOrder, dominostest.Failure{
+			StatusCode: http.StatusServiceUnavailable,
+			Times:      2,
+		})
+
+		if _, err := client.PriceOrder(ctx, newOrder()); err != nil {
+			t.Fatalf("price order: %v", err)
+		}
+
+		if n := srv.Requests(dominostest.EndpointPriceOrder); n != 3 {
+			t.Errorf("expected 3 requests, got %d", n)
+		}
+	})
+
+	t.Run("server errors are not retried when placing orders", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+			StatusCode: http.StatusServiceUnavailable,
+			Times:      1,
+		})
+
+		_, err := client.PlaceOrder(ctx, newOrder())
+		if err == nil || dominos.IsNotSent(err) || !dominos.IsRetryable(err) {
+			t.Errorf("expected a retryable error that might have been sent, got %v", err)
+		}
+
+		if n := srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("rejections are not retried", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPriceOrder, dominostest.Failure{
+			Code: "StoreClosed",
+		})
+
+		_, err := client.PriceOrder(ctx, newOrder())
+
+		var apiErr *dominos.APIError
+		if !errors.As(err, &apiErr) {
+			t.Fatalf("expected an api error, got %v", err)
+		}
+
+		if apiErr.Code() != "StoreClosed" || apiErr.Reason() != "StoreClosed" {
+			t.Errorf("expected StoreClosed, got code %q and reason %q", apiErr.Code(), apiErr.Reason())
+		}
+
+		if dominos.IsRetryable(err) || dominos.IsNotSent(err) {
+			t.Errorf("expected a rejection, got %v", err)
+		}
+
+		if n := srv.Requests(dominostest.EndpointPriceOrder); n != 1 {
+			t.Errorf("expected 1 request, got %d", n)
+		}
+	})
+
+	t.Run("unapplied coupons are told apart", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		order := newOrder()
+		order.Coupons = []string{"9193"}
+
+		price, err := client.PriceOrder(ctx, order)
+		if err != nil {
+			t.Fatalf("price order: %v", err)
+		}
+
+		if len(price.Coupons) != 1 || !price.Coupons[0].Applied() {
+			t.Errorf("expected coupon 9193 to be applied, got %+v", price.Coupons)
+		}
+
+		if price.Total != price.Subtotal-price.Discounts+price.Fees+price.Tax {
+			t.Errorf("expected the total to add up, got %+v", price)
+		}
+	})
+}
+
+func TestRequestErrorMetrics(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	metrics := dominos.NewMetrics()
+
+	client, err := dominos.NewClient(srv.URL,
+		dominos.WithCountry(dominos.CountryCanada),
+		dominos.WithRetryPolicy(dominos.NoRetries),
+		dominos.WithMetrics(metrics),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	// rejections come with a successful status code, ...
+	//
+	srv.Fail(dominostest.EndpointPriceOrder, dominostest.Failure{
+		Code:  "StoreClosed",
+		Times: 1,
+	})
+
+	if _, err := client.PriceOrder(ctx, newOrder()); err == nil {
+		t.Fatal("expected pricing the order to fail")
+	}
+
+	// ... unlike server errors, ...
+	//
+	srv.Fail(dominostest.EndpointPriceOrder, dominostest.Failure{
+		StatusCode: http.StatusServiceUnavailable,
+		Times:      1,
+	})
+
+	if _, err := client.PriceOrder(ctx, newOrder()); err == nil {
+		t.Fatal("expected pricing the order to fail")
+	}
+
+	// ... and both get counted, but not successful requests.
+	//
+	if _, err := client.PriceOrder(ctx, newOrder()); err != nil {
+		t.Fatalf("price order: %v", err)
+	}
+
+	for code, expected := range map[string]float64{
+		"StoreClosed": 1,
+		"503":         1,
+		"200":         0,
+	} {
+		res := testutil.ToFloat64(metrics.RequestErrors.WithLabelValues("price-order", code))
+		if res != expected {
+			t.Errorf("code %s: expected %v error(s), got %v", code, expected, res)
+		}
+	}
+
+	if n := testutil.CollectAndCount(metrics.RequestDuration); n != 1 {
+		t.Errorf("expected the duration of requests to one endpoint to be observed, got %d", n)
+	}
+}
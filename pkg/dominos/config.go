@@ -0,0 +1,124 @@
+package dominos
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"golang.org/x/time/rate"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+)
+
+// Config configures how clients reach Domino's API on behalf of customers,
+// be it from the controller or from pizzactl.
+type Config struct {
+	// DefaultCountry is the country assumed for customers that neither
+	// specify one nor have an address it can be derived from.
+	DefaultCountry Country
+
+	// URL, if set, is used as the API base URL for every customer,
+	// regardless of their country.
+	URL string
+
+	// RequestTimeout is how long each request to Domino's can take before
+	// it's given up on (defaults to DefaultRequestTimeout).
+	RequestTimeout time.Duration
+
+	// RetryPolicy is how requests that are safe to be repeated get retried
+	// (defaults to DefaultRetryPolicy).
+	RetryPolicy RetryPolicy
+
+	// RateLimiter, if set, limits the rate at which requests go out to
+	// Domino's, shared across all of the clients.
+	RateLimiter *rate.Limiter
+
+	// Debug, if set, has the requests made to Domino's (and the responses
+	// to them) logged, redacted, at DebugVerbosity.
+	Debug bool
+
+	// HARDirectory, if set along with Debug, is where the requests made to
+	// Domino's get captured as HAR files.
+	HARDirectory string
+}
+
+// NewClient instantiates a client targetting the API that serves the
+// customer's country, with `opts` applied on top of the configuration.
+func (c Config) NewClient(customer *v1alpha1.PizzaCustomer, opts ...Option) (*Client, error) {
+	country := c.Country(customer)
+
+	url := c.URL
+	if url == "" {
+		var err error
+
+		url, err = URLForCountry(country)
+		if err != nil {
+			return nil, fmt.Errorf("url for country '%s': %w", country, err)
+		}
+	}
+
+	configured := []Option{
+		WithCountry(country),
+		WithLanguage(c.Language(customer)),
+	}
+	if c.RequestTimeout != 0 {
+		configured = append(configured, WithRequestTimeout(c.RequestTimeout))
+	}
+	if c.RetryPolicy != nil {
+		configured = append(configured, WithRetryPolicy(c.RetryPolicy))
+	}
+	if c.RateLimiter != nil {
+		configured = append(configured, WithRateLimiter(c.RateLimiter))
+	}
+	if c.Debug {
+		configured = append(configured, WithDebug())
+	}
+	if c.HARDirectory != "" {
+		configured = append(configured, WithHARDirectory(c.HARDirectory))
+	}
+
+	client, err := NewClient(url, append(configured, opts...)...)
+	if err != nil {
+		return nil, fmt.Errorf("new client: %w", err)
+	}
+
+	return client, nil
+}
+
+// Country determines the country that a customer is in.
+func (c Config) Country(customer *v1alpha1.PizzaCustomer) Country {
+	if customer.Spec.Country != "" {
+		return Country(customer.Spec.Country)
+	}
+
+	country, found := CountryForAddress(CustomerAddress(customer))
+	if found {
+		return country
+	}
+
+	return c.DefaultCountry
+}
+
+// Language determines the language that the orders of a customer are placed
+// in: French for customers in Quebec, English for everyone else. Menus, on
+// the other hand, are retrieved in the language of the store (see
+// LanguageForStore).
+func (c Config) Language(customer *v1alpha1.PizzaCustomer) string {
+	if strings.EqualFold(strings.TrimSpace(customer.Spec.State), "QC") {
+		return "fr"
+	}
+
+	return DefaultLanguage
+}
+
+// CustomerAddress retrieves the address of a customer in the form expected
+// by the client.
+func CustomerAddress(customer *v1alpha1.PizzaCustomer) Address {
+	return Address{
+		StreetNumber: customer.Spec.StreetNumber,
+		StreetName:   customer.Spec.StreetName,
+		City:         customer.Spec.City,
+		State:        customer.Spec.State,
+		Zip:          customer.Spec.Zip,
+	}
+}
@@ -0,0 +1,155 @@
+package dominos
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// DebugVerbosity is the verbosity level at which requests and responses
+// get logged.
+const DebugVerbosity = 1
+
+// DebugTransport logs the requests that go through it (and the responses to
+// them) with personal information and payment details redacted, optionally
+// capturing each exchange as a HAR file too.
+type DebugTransport struct {
+	r      http.RoundTripper
+	log    logr.Logger
+	harDir string
+}
+
+func (d *DebugTransport) RoundTrip(h *http.Request) (*http.Response, error) {
+	log := d.log.V(DebugVerbosity)
+	if !log.Enabled() && d.harDir == "" {
+		return d.r.RoundTrip(h)
+	}
+
+	reqBody, err := requestBody(h)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	log.Info("request",
+		"method", h.Method,
+		"url", redactURL(h.URL),
+		"headers", redactHeaders(h.Header),
+		"body", redactBody(reqBody),
+	)
+
+	start := time.Now()
+	resp, err := d.r.RoundTrip(h)
+	elapsed := time.Since(start)
+
+	if err != nil {
+		log.Info("request failed",
+			"method", h.Method,
+			"url", redactURL(h.URL),
+			"elapsed", elapsed.String(),
+			"error", err.Error(),
+		)
+
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	log.Info("response",
+		"method", h.Method,
+		"url", redactURL(h.URL),
+		"status", resp.StatusCode,
+		"elapsed", elapsed.String(),
+		"headers", redactHeaders(resp.Header),
+		"body", redactBody(respBody),
+	)
+
+	if d.harDir != "" {
+		if err := d.capture(h, reqBody, resp, respBody, start, elapsed); err != nil {
+			d.log.Error(err, "har capture",
+				"method", h.Method,
+				"url", redactURL(h.URL),
+			)
+		}
+	}
+
+	return resp, nil
+}
+
+// capture writes an exchange to a HAR file in the capture directory, named
+// after when the request started and the endpoint it was for.
+func (d *DebugTransport) capture(
+	req *http.Request, reqBody []byte,
+	resp *http.Response, respBody []byte,
+	start time.Time, elapsed time.Duration,
+) error {
+	if err := os.MkdirAll(d.harDir, 0o700); err != nil {
+		return fmt.Errorf("mkdir '%s': %w", d.harDir, err)
+	}
+
+	content, err := json.MarshalIndent(newHAR(req, reqBody, resp, respBody, start, elapsed), "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshal: %w", err)
+	}
+
+	fname := filepath.Join(d.harDir, fmt.Sprintf("%s-%s.har",
+		start.UTC().Format("20060102T150405.000000000Z"), endpointName(req.URL.Path),
+	))
+
+	if err := ioutil.WriteFile(fname, content, 0o600); err != nil {
+		return fmt.Errorf("write '%s': %w", fname, err)
+	}
+
+	return nil
+}
+
+// requestBody reads the body of a request, leaving it in place to still be
+// sent.
+func requestBody(h *http.Request) ([]byte, error) {
+	if h.Body == nil || h.Body == http.NoBody {
+		return nil, nil
+	}
+
+	if h.GetBody != nil {
+		body, err := h.GetBody()
+		if err != nil {
+			return nil, err
+		}
+
+		defer body.Close()
+		return ioutil.ReadAll(body)
+	}
+
+	content, err := ioutil.ReadAll(h.Body)
+	h.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	h.Body = ioutil.NopCloser(bytes.NewReader(content))
+
+	return content, nil
+}
+
+// nopLogger is the logger of clients that haven't been given one, discarding
+// everything.
+type nopLogger struct{}
+
+func (nopLogger) Enabled() bool                           { return false }
+func (nopLogger) Info(string, ...interface{})             {}
+func (nopLogger) Error(error, string, ...interface{})     {}
+func (l nopLogger) V(int) logr.Logger                     { return l }
+func (l nopLogger) WithValues(...interface{}) logr.Logger { return l }
+func (l nopLogger) WithName(string) logr.Logger           { return l }
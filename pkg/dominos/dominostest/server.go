@@ -0,0 +1,828 @@
+// Package dominostest provides a fake Domino's API server that serves the
+// endpoints used by dominos.Client from in-memory fixtures, so that the client
+// and the reconcilers built on top of it can be exercised without network
+// access.
+//
+//	srv := dominostest.NewServer()
+//	defer srv.Close()
+//
+//	srv.AddStore(dominostest.Store{
+//		ID:       "10391",
+//		Carryout: true,
+//		Products: []dominostest.Product{
+//			{Code: "10SCREEN", Name: "Small Hand Tossed", Price: 9.03},
+//		},
+//	})
+//
+//	client, _ := dominos.NewClient(srv.URL)
+package dominostest
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/dominos/internal/api"
+)
+
+type Endpoint string
+
+const (
+	EndpointStoreLocator Endpoint = "store-locator"
+	EndpointStoreMenu    Endpoint = "store-menu"
+	EndpointPriceOrder   Endpoint = "price-order"
+	EndpointPlaceOrder   Endpoint = "place-order"
+	EndpointTracker      Endpoint = "tracker"
+)
+
+// Store is the fixture for a single Domino's location along with the menu
+// it serves.
+type Store struct {
+	ID      string
+	Phone   string
+	Address string
+
+	// Closed makes the store show up in the store locator as not open.
+	Closed bool
+
+	Carryout        bool
+	Delivery        bool
+	DriveUpCarryout bool
+
+	// PaymentTypes are the payment types accepted by the store. When not
+	// set, all of them are.
+	PaymentTypes []dominos.PaymentType
+
+	// TaxRate is the rate (e.g., 0.13) at which orders from the store get
+	// taxed, fees included.
+	TaxRate float64
+
+	// DeliveryFee is charged on top of the products of delivery orders.
+	DeliveryFee float64
+
+	Products []Product
+	Coupons  []Coupon
+}
+
+// Coupon is a coupon offered by a store.
+type Coupon struct {
+	Code        string
+	Name        string
+	Description string
+
+	// Discount is taken off of the price of orders the coupon applies to.
+	Discount float64
+
+	// Products are the codes of the products that an order must have for
+	// the coupon to apply to it (reported as `CouponNotFulfilled`
+	// otherwise).
+	Products []string
+
+	// Expired makes orders with the coupon fail to be priced or placed,
+	// with `CouponExpired` as the reason.
+	Expired bool
+}
+
+// Product is a product on a store's menu. Products that list toppings are
+// served as customizable ones, while the rest are served as pre-configured.
+type Product struct {
+	Code        string
+	Name        string
+	Description string
+	Size        string
+	Price       float64
+
+	// Type is the kind of product (e.g., "Pizza").
+	Type string
+
+	// Toppings are the codes of the toppings that can be added to the
+	// product, while DefaultToppings are the ones it comes with.
+	Toppings        []string
+	DefaultToppings []string
+}
+
+// Failure describes how an endpoint should misbehave.
+type Failure struct {
+	// StatusCode, if set, makes the endpoint reply with that HTTP status
+	// code and an empty body.
+	StatusCode int
+
+	// Code, if set, makes the endpoint reply with `Status: -1`, reporting
+	// `Code` as the reason for the failure.
+	Code string
+
+	// Times is the number of requests that should fail before the endpoint
+	// goes back to behaving normally. Zero means "fail forever".
+	Times int
+
+	// AfterPlacing, if set along with StatusCode, makes the place-order
+	// endpoint place the order before replying with the failure, as if
+	// the response got lost on its way back.
+	AfterPlacing bool
+}
+
+// Order is an order that has been successfully placed against the server.
+type Order struct {
+	ID            string
+	Key           string
+	StoreID       string
+	ServiceMethod string
+	FirstName     string
+	LastName      string
+	Email         string
+	Phone         string
+	Products      []OrderProduct
+	Payments      []Payment
+	Amount        dominos.Money
+
+	// Stage is the stage that the order tracker reports the order at
+	// (see SetOrderStage).
+	Stage dominos.OrderStage
+}
+
+type Payment struct {
+	Type     string
+	CardType string
+	Amount   float64
+}
+
+type OrderProduct struct {
+	Code string
+	Qty  int
+}
+
+type Server struct {
+	URL string
+
+	srv *httptest.Server
+
+	mu       sync.Mutex
+	stores   []Store
+	failures map[Endpoint]*Failure
+	requests map[Endpoint]int
+	orders   []Order
+}
+
+// NewServer starts a fake Domino's API server with no stores. Callers
+// should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		failures: map[Endpoint]*Failure{},
+		requests: map[Endpoint]int{},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(dominos.PathStoreLocator, s.handleStoreLocator)
+	mux.HandleFunc("/power/store/", s.handleStoreMenu)
+	mux.HandleFunc(dominos.PathPriceOrder, s.handlePriceOrder)
+	mux.HandleFunc(dominos.PathPlaceOrder, s.handlePlaceOrder)
+	mux.HandleFunc(dominos.PathTracker, s.handleTracker)
+
+	s.srv = httptest.NewServer(mux)
+	s.URL = s.srv.URL
+
+	return s
+}
+
+func (s *Server) Close() {
+	s.srv.Close()
+}
+
+// AddStore adds a store to the server. Stores are returned by the store
+// locator in the order they were added, regardless of the address searched
+// for.
+func (s *Server) AddStore(store Store) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stores = append(s.stores, store)
+}
+
+// Fail configures an endpoint to fail according to `failure`.
+func (s *Server) Fail(endpoint Endpoint, failure Failure) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.failures[endpoint] = &failure
+}
+
+// Requests retrieves the number of requests received by an endpoint,
+// including the ones that failed.
+func (s *Server) Requests(endpoint Endpoint) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.requests[endpoint]
+}
+
+// Orders retrieves the orders successfully placed so far.
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]Order{}, s.orders...)
+}
+
+// SetOrderStage makes the order tracker report an order (by its ID) at a
+// given stage. Orders start at dominos.OrderStageMaking once placed.
+func (s *Server) SetOrderStage(id string, stage dominos.OrderStage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for idx := range s.orders {
+		if s.orders[idx].ID == id {
+			s.orders[idx].Stage = stage
+		}
+	}
+}
+
+func (s *Server) handleStoreLocator(w http.ResponseWriter, r *http.Request) {
+	failure, ok := s.receive(EndpointStoreLocator)
+	if ok && failure.StatusCode != 0 {
+		w.WriteHeader(failure.StatusCode)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := api.StoreLocatorResponse{
+		Granularity: "Exact",
+		Stores:      []api.Store{},
+	}
+	for _, store := range s.stores {
+		resp.Stores = append(resp.Stores, s.locatorStore(store))
+	}
+
+	// just like Domino's, the address searched for is echoed back, as
+	// understood: `s` being "<number> <name>", and `c` "<city>, <region>
+	// <postal code>".
+	//
+	street := strings.TrimSpace(r.URL.Query().Get("s"))
+	resp.Address.Street = street
+	resp.Address.StreetNumber, resp.Address.StreetName = splitFirst(street, " ")
+
+	var rest string
+	resp.Address.City, rest = splitFirst(r.URL.Query().Get("c"), ",")
+	resp.Address.Region, resp.Address.PostalCode = splitFirst(rest, " ")
+
+	reply(w, &resp)
+}
+
+func (s *Server) handleStoreMenu(w http.ResponseWriter, r *http.Request) {
+	storeID := strings.TrimSuffix(
+		strings.TrimPrefix(r.URL.Path, "/power/store/"), "/menu",
+	)
+
+	failure, ok := s.receive(EndpointStoreMenu)
+	if ok && failure.StatusCode != 0 {
+		w.WriteHeader(failure.StatusCode)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	store, found := s.store(storeID)
+	if !found {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	resp := api.MenuResponse{
+		Products:      map[string]*api.Product{},
+		Variants:      map[string]*api.Variant{},
+		Preconfigured: map[string]*api.PreConfiguredProduct{},
+		Coupons:       map[string]*api.Coupon{},
+	}
+	for _, coupon := range store.Coupons {
+		resp.Coupons[coupon.Code] = &api.Coupon{
+			ItemCommon: api.ItemCommon{
+				Code: coupon.Code,
+				Name: coupon.Name,
+			},
+			Description: coupon.Description,
+			Price:       fmt.Sprintf("%.2f", coupon.Discount),
+		}
+	}
+	for _, product := range store.Products {
+		if len(product.Toppings) != 0 {
+			resp.Products[product.Code] = &api.Product{
+				ItemCommon: api.ItemCommon{
+					Code: product.Code,
+					Name: product.Name,
+				},
+				Variants:          []string{product.Code},
+				Description:       product.Description,
+				ProductType:       product.Type,
+				AvailableToppings: strings.Join(product.Toppings, ","),
+				DefaultToppings:   strings.Join(product.DefaultToppings, ","),
+			}
+			resp.Variants[product.Code] = &api.Variant{
+				ItemCommon: api.ItemCommon{
+					Code: product.Code,
+					Name: product.Name,
+				},
+				Price:       fmt.Sprintf("%.2f", product.Price),
+				ProductCode: product.Code,
+			}
+
+			continue
+		}
+
+		resp.Preconfigured[product.Code] = &api.PreConfiguredProduct{
+			ItemCommon: api.ItemCommon{
+				Code: product.Code,
+				Name: product.Name,
+			},
+			Description: product.Description,
+			Size:        product.Size,
+		}
+	}
+
+	reply(w, &resp)
+}
+
+func (s *Server) handlePriceOrder(w http.ResponseWriter, r *http.Request) {
+	failure, failing := s.receive(EndpointPriceOrder)
+	if failing && failure.StatusCode != 0 {
+		w.WriteHeader(failure.StatusCode)
+		return
+	}
+
+	msg := api.OrderMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := api.PriceResponse{}
+
+	code := failure.Code
+	if !failing {
+		var p pricing
+
+		p, code = s.price(msg.Order)
+		resp.Order.Amounts = p.amounts
+		resp.Order.Coupons = p.coupons
+	}
+
+	if code != "" {
+		resp.Status = -1
+		resp.Order.CorrectiveAction.Code = code
+	}
+
+	reply(w, &resp)
+}
+
+func (s *Server) handlePlaceOrder(w http.ResponseWriter, r *http.Request) {
+	failure, failing := s.receive(EndpointPlaceOrder)
+	if failing && failure.AfterPlacing {
+		failing = false
+		w = &failingResponseWriter{ResponseWriter: w, statusCode: failure.StatusCode}
+	}
+
+	if failing && failure.StatusCode != 0 {
+		w.WriteHeader(failure.StatusCode)
+		return
+	}
+
+	msg := api.OrderMessage{}
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := api.PlaceOrderResponse{}
+
+	var total dominos.Money
+
+	code := failure.Code
+	if !failing {
+		var p pricing
+
+		p, code = s.price(msg.Order)
+		resp.Order.Amounts = p.amounts
+		total = p.total
+	}
+
+	if code != "" {
+		resp.Status = -1
+		resp.Order.StatusItems = api.StatusItems{
+			{Code: code},
+		}
+
+		reply(w, &resp)
+		return
+	}
+
+	// orders placed with a key that has been seen before are not placed
+	// again.
+	//
+	if existing, found := s.order(msg.Order.OrderID); found {
+		resp.Order.OrderID = existing.ID
+		resp.Order.EstimatedWaitMinutes = "15-25"
+
+		reply(w, &resp)
+		return
+	}
+
+	order := Order{
+		ID:            fmt.Sprintf("fake-order-%d", len(s.orders)+1),
+		Key:           msg.Order.OrderID,
+		StoreID:       msg.Order.StoreID,
+		ServiceMethod: msg.Order.ServiceMethod,
+		FirstName:     msg.Order.FirstName,
+		LastName:      msg.Order.LastName,
+		Email:         msg.Order.Email,
+		Phone:         msg.Order.Phone,
+		Amount:        total,
+		Stage:         dominos.OrderStageMaking,
+	}
+	for _, product := range msg.Order.Products {
+		order.Products = append(order.Products, OrderProduct{
+			Code: product.Code,
+			Qty:  product.Qty,
+		})
+	}
+	for _, payment := range msg.Order.Payments {
+		order.Payments = append(order.Payments, Payment{
+			Type:     payment.Type,
+			CardType: payment.CardType,
+			Amount:   payment.Amount,
+		})
+	}
+
+	s.orders = append(s.orders, order)
+
+	resp.Order.OrderID = order.ID
+	resp.Order.EstimatedWaitMinutes = "15-25"
+
+	reply(w, &resp)
+}
+
+func (s *Server) handleTracker(w http.ResponseWriter, r *http.Request) {
+	failure, ok := s.receive(EndpointTracker)
+	if ok && failure.StatusCode != 0 {
+		w.WriteHeader(failure.StatusCode)
+		return
+	}
+
+	phone := r.URL.Query().Get("Phone")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	resp := api.TrackerResponse{}
+	for _, order := range s.orders {
+		if digits(order.Phone) != phone {
+			continue
+		}
+
+		status := api.TrackerOrderStatus{
+			StoreID:       order.StoreID,
+			OrderID:       order.ID,
+			OrderKey:      order.Key,
+			Phone:         phone,
+			ServiceMethod: order.ServiceMethod,
+			OrderStatus:   string(order.Stage),
+		}
+
+		// stage times are all the same, as stages are only ever
+		// advanced through SetOrderStage.
+		//
+		now := time.Now().Format("2006-01-02T15:04:05")
+		for _, stage := range dominos.OrderStages {
+			switch stage {
+			case dominos.OrderStageMaking:
+				status.StartTime = now
+			case dominos.OrderStageOven:
+				status.OvenTime = now
+			case dominos.OrderStageQualityCheck:
+				status.RackTime = now
+			case dominos.OrderStageOutForDelivery:
+				status.RouteTime = now
+			case dominos.OrderStageComplete:
+				status.DeliveryTime = now
+			}
+
+			if stage == order.Stage {
+				break
+			}
+		}
+
+		resp.OrderStatuses = append(resp.OrderStatuses, status)
+	}
+
+	w.Header().Set("Content-Type", "text/xml")
+	if err := xml.NewEncoder(w).Encode(&resp); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// failingResponseWriter replies with a status code and an empty body,
+// regardless of what's written to it.
+type failingResponseWriter struct {
+	http.ResponseWriter
+	statusCode  int
+	wroteHeader bool
+}
+
+func (w *failingResponseWriter) WriteHeader(int) {
+	if w.wroteHeader {
+		return
+	}
+
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(w.statusCode)
+}
+
+func (w *failingResponseWriter) Write(b []byte) (int, error) {
+	w.WriteHeader(w.statusCode)
+	return len(b), nil
+}
+
+// receive accounts for a request to an endpoint, returning the failure that
+// it should reply with, if any.
+func (s *Server) receive(endpoint Endpoint) (Failure, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.requests[endpoint]++
+
+	failure, found := s.failures[endpoint]
+	if !found {
+		return Failure{}, false
+	}
+
+	if failure.Times > 0 {
+		failure.Times--
+		if failure.Times == 0 {
+			delete(s.failures, endpoint)
+		}
+	}
+
+	return *failure, true
+}
+
+// pricing is what an order got priced at.
+type pricing struct {
+	amounts api.Amounts
+	total   dominos.Money
+	coupons []api.CouponStatus
+}
+
+// price computes what an order costs, or the code explaining why it can't
+// be priced.
+//
+// Must be called with `s.mu` held.
+func (s *Server) price(order api.Order) (pricing, string) {
+	store, found := s.store(order.StoreID)
+	if !found {
+		return pricing{}, "StoreNotFound"
+	}
+
+	if store.Closed {
+		return pricing{}, "StoreClosed"
+	}
+
+	if !serves(store, order.ServiceMethod) {
+		return pricing{}, "ServiceMethodNotAllowed"
+	}
+
+	for _, payment := range order.Payments {
+		if !accepts(store, dominos.PaymentType(payment.Type)) {
+			return pricing{}, "PaymentTypeNotAllowed"
+		}
+	}
+
+	var menu dominos.Money
+	codes := map[string]bool{}
+	for _, orderProduct := range order.Products {
+		product, found := findProduct(store, orderProduct.Code)
+		if !found {
+			return pricing{}, "PosOrderIncomplete"
+		}
+
+		menu += cents(product.Price) * dominos.Money(orderProduct.Qty)
+		codes[product.Code] = true
+	}
+
+	p := pricing{}
+
+	var discount dominos.Money
+	for _, orderCoupon := range order.Coupons {
+		coupon, found := findCoupon(store, orderCoupon.Code)
+		if !found {
+			return p, "CouponNotFound"
+		}
+
+		status := api.CouponStatus{Code: coupon.Code}
+
+		switch {
+		case coupon.Expired:
+			status.Status = -1
+			status.StatusItems = api.StatusItems{{Code: "CouponExpired"}}
+		case !containsAll(codes, coupon.Products):
+			status.StatusItems = api.StatusItems{{Code: "CouponNotFulfilled"}}
+		default:
+			status.StatusItems = api.StatusItems{{Code: "CouponFulfilled"}}
+			discount += cents(coupon.Discount)
+		}
+
+		p.coupons = append(p.coupons, status)
+
+		if coupon.Expired {
+			return p, "CouponExpired"
+		}
+	}
+
+	if discount > menu {
+		discount = menu
+	}
+
+	var surcharge dominos.Money
+	if order.ServiceMethod == string(dominos.ServiceDelivery) {
+		surcharge = cents(store.DeliveryFee)
+	}
+
+	net := menu - discount + surcharge
+	tax := cents(net.Float64() * store.TaxRate)
+
+	p.total = net + tax
+	p.amounts = api.Amounts{
+		Menu:      json.Number(menu.String()),
+		Discount:  json.Number(discount.String()),
+		Surcharge: json.Number(surcharge.String()),
+		Net:       json.Number(net.String()),
+		Tax:       json.Number(tax.String()),
+		Customer:  json.Number(p.total.String()),
+	}
+
+	return p, ""
+}
+
+func cents(amount float64) dominos.Money {
+	return dominos.Money(math.Round(amount * 100))
+}
+
+// Must be called with `s.mu` held.
+func (s *Server) store(id string) (Store, bool) {
+	for _, store := range s.stores {
+		if store.ID == id {
+			return store, true
+		}
+	}
+
+	return Store{}, false
+}
+
+// order retrieves a placed order by the key it was placed with.
+//
+// Must be called with `s.mu` held.
+func (s *Server) order(key string) (Order, bool) {
+	if key == "" {
+		return Order{}, false
+	}
+
+	for _, order := range s.orders {
+		if order.Key == key {
+			return order, true
+		}
+	}
+
+	return Order{}, false
+}
+
+func serves(store Store, serviceMethod string) bool {
+	switch dominos.Service(serviceMethod) {
+	case dominos.ServiceCarryout:
+		return store.Carryout
+	case dominos.ServiceDelivery:
+		return store.Delivery
+	case dominos.ServiceDriveUpCarryout:
+		return store.DriveUpCarryout
+	}
+
+	return false
+}
+
+func accepts(store Store, paymentType dominos.PaymentType) bool {
+	for _, accepted := range paymentTypes(store) {
+		if accepted == paymentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+func paymentTypes(store Store) []dominos.PaymentType {
+	if len(store.PaymentTypes) != 0 {
+		return store.PaymentTypes
+	}
+
+	return []dominos.PaymentType{
+		dominos.PaymentTypeCash,
+		dominos.PaymentTypeDoorCredit,
+		dominos.PaymentTypeCreditCard,
+		dominos.PaymentTypeGiftCard,
+	}
+}
+
+func (s *Server) locatorStore(store Store) api.Store {
+	res := api.Store{
+		StoreID:             store.ID,
+		Phone:               store.Phone,
+		AddressDescription:  store.Address,
+		IsOpen:              !store.Closed,
+		IsOnlineCapable:     true,
+		IsOnlineNow:         !store.Closed,
+		IsDeliveryStore:     store.Delivery,
+		AllowCarryoutOrders: store.Carryout,
+		AllowDeliveryOrders: store.Delivery,
+		AllowDuc:            store.DriveUpCarryout,
+	}
+
+	for _, paymentType := range paymentTypes(store) {
+		res.AcceptablePaymentTypes = append(res.AcceptablePaymentTypes, string(paymentType))
+	}
+
+	res.ServiceIsOpen.Carryout = store.Carryout && !store.Closed
+	res.ServiceIsOpen.Delivery = store.Delivery && !store.Closed
+	res.ServiceIsOpen.DriveUpCarryout = store.DriveUpCarryout && !store.Closed
+
+	return res
+}
+
+// splitFirst splits a string around the first occurrence of a separator,
+// trimming the spaces around both parts.
+func splitFirst(str, sep string) (string, string) {
+	parts := strings.SplitN(strings.TrimSpace(str), sep, 2)
+	if len(parts) == 1 {
+		return parts[0], ""
+	}
+
+	return strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+}
+
+func findProduct(store Store, code string) (Product, bool) {
+	for _, product := range store.Products {
+		if product.Code == code {
+			return product, true
+		}
+	}
+
+	return Product{}, false
+}
+
+func findCoupon(store Store, code string) (Coupon, bool) {
+	for _, coupon := range store.Coupons {
+		if coupon.Code == code {
+			return coupon, true
+		}
+	}
+
+	return Coupon{}, false
+}
+
+func containsAll(set map[string]bool, items []string) bool {
+	for _, item := range items {
+		if !set[item] {
+			return false
+		}
+	}
+
+	return true
+}
+
+func digits(str string) string {
+	res := strings.Builder{}
+	for _, c := range str {
+		if c >= '0' && c <= '9' {
+			res.WriteRune(c)
+		}
+	}
+
+	return res.String()
+}
+
+func reply(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
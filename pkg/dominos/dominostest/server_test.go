@@ -0,0 +1,363 @@
+package dominostest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/dominos/dominostest"
+)
+
+const phone = "416-555-0199"
+
+func newServer(t *testing.T) *dominostest.Server {
+	srv := dominostest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddStore(dominostest.Store{
+		ID:          "10391",
+		Address:     "150 Front St W\nToronto, ON M5J 2N1",
+		Carryout:    true,
+		Delivery:    true,
+		TaxRate:     0.13,
+		DeliveryFee: 2.99,
+		Products: []dominostest.Product{
+			{Code: "10SCREEN", Name: "Small Hand Tossed", Price: 9.03, Toppings: []string{"X", "C"}},
+			{Code: "2LSPRITE", Name: "Sprite", Size: "2 Litre", Price: 3.49},
+		},
+		Coupons: []dominostest.Coupon{
+			{Code: "9193", Discount: 1, Products: []string{"10SCREEN"}},
+		},
+	})
+
+	srv.AddStore(dominostest.Store{
+		ID:       "10392",
+		Closed:   true,
+		Delivery: true,
+	})
+
+	return srv
+}
+
+func newClient(t *testing.T, url string) *dominos.Client {
+	client, err := dominos.NewClient(url,
+		dominos.WithCountry(dominos.CountryCanada),
+		dominos.WithRetryPolicy(dominos.NoRetries),
+	)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+
+	return client
+}
+
+func newOrder() dominos.Order {
+	return dominos.Order{
+		StoreID: "10391",
+		PersonalInformation: dominos.PersonalInformation{
+			FirstName: "barack",
+			LastName:  "obama",
+			Email:     "barack@example.com",
+			Phone:     phone,
+		},
+		Products: []dominos.Product{
+			{ID: "10SCREEN", Quantity: 1},
+		},
+		Service:     dominos.ServiceCarryout,
+		PaymentType: dominos.PaymentTypeCash,
+		Amount:      1020,
+		Key:         "c0ffee",
+	}
+}
+
+func TestStoreLocator(t *testing.T) {
+	srv := newServer(t)
+	client := newClient(t, srv.URL)
+
+	stores, err := client.LocateStores(context.Background(),
+		dominos.Address{City: "Toronto", State: "ON", Zip: "M5J 0A9"},
+		dominos.ServiceDelivery,
+	)
+	if err != nil {
+		t.Fatalf("locate stores: %v", err)
+	}
+
+	if len(stores) != 2 {
+		t.Fatalf("expected 2 stores, got %d", len(stores))
+	}
+
+	if stores[0].ID != "10391" || !stores[0].Open {
+		t.Errorf("expected the first store to be 10391, open, got %+v", stores[0])
+	}
+
+	if stores[1].ID != "10392" || stores[1].Open {
+		t.Errorf("expected the second store to be 10392, closed, got %+v", stores[1])
+	}
+}
+
+func TestStoreMenu(t *testing.T) {
+	srv := newServer(t)
+	client := newClient(t, srv.URL)
+
+	menu, err := client.StoreMenu(context.Background(), "10391")
+	if err != nil {
+		t.Fatalf("store menu: %v", err)
+	}
+
+	// products that list toppings are customizable, with a variant of
+	// the same code, while the rest are pre-configured.
+	//
+	if _, found := menu.Product("10SCREEN"); !found {
+		t.Errorf("expected 10SCREEN to be a customizable product")
+	}
+
+	variant, found := menu.Variant("10SCREEN")
+	if !found || variant.Price != "9.03" {
+		t.Errorf("expected 10SCREEN to be a variant priced at 9.03, got %+v", variant)
+	}
+
+	if len(menu.Preconfigured) != 1 || menu.Preconfigured[0].ID != "2LSPRITE" {
+		t.Errorf("expected 2LSPRITE to be the only pre-configured product, got %+v", menu.Preconfigured)
+	}
+
+	if _, found := menu.Coupon("9193"); !found {
+		t.Errorf("expected coupon 9193 to be in the menu")
+	}
+
+	if _, err := client.StoreMenu(context.Background(), "404"); err == nil {
+		t.Errorf("expected the menu of an unknown store to fail")
+	}
+}
+
+func TestPriceOrder(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		modify func(*dominos.Order)
+		total  dominos.Money
+		reason string
+	}{
+		{
+			name:  "taxed",
+			total: 1020,
+		},
+		{
+			name:   "coupon applied",
+			modify: func(o *dominos.Order) { o.Coupons = []string{"9193"} },
+			total:  907,
+		},
+		{
+			name: "delivery",
+			modify: func(o *dominos.Order) {
+				o.Service = dominos.ServiceDelivery
+				o.Address = dominos.Address{
+					StreetNumber: "90",
+					StreetName:   "Bremner Blvd",
+					City:         "Toronto",
+					State:        "ON",
+					Zip:          "M5J 0A9",
+				}
+			},
+			total: 1358,
+		},
+		{
+			name:   "unknown store",
+			modify: func(o *dominos.Order) { o.StoreID = "404" },
+			reason: "StoreNotFound",
+		},
+		{
+			name:   "closed store",
+			modify: func(o *dominos.Order) { o.StoreID = "10392" },
+			reason: "StoreClosed",
+		},
+		{
+			name:   "unknown product",
+			modify: func(o *dominos.Order) { o.Products[0].ID = "14SCREEN" },
+			reason: "PosOrderIncomplete",
+		},
+		{
+			name:   "unknown coupon",
+			modify: func(o *dominos.Order) { o.Coupons = []string{"0000"} },
+			reason: "CouponNotFound",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			srv := newServer(t)
+			client := newClient(t, srv.URL)
+
+			order := newOrder()
+			if tc.modify != nil {
+				tc.modify(&order)
+			}
+
+			price, err := client.PriceOrder(context.Background(), order)
+			if tc.reason != "" {
+				apiErr := &dominos.APIError{}
+				if !errors.As(err, &apiErr) {
+					t.Fatalf("expected an api error, got %v", err)
+				}
+
+				if apiErr.Code() != tc.reason {
+					t.Errorf("expected '%s' as the reason, got '%s'", tc.reason, apiErr.Code())
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("price order: %v", err)
+			}
+
+			if price.Total != tc.total {
+				t.Errorf("expected a total of %s, got %s", tc.total, price.Total)
+			}
+		})
+	}
+}
+
+func TestPlaceOrder(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	client := newClient(t, srv.URL)
+
+	placed, err := client.PlaceOrder(ctx, newOrder())
+	if err != nil {
+		t.Fatalf("place order: %v", err)
+	}
+
+	// orders placed again with the same key are not placed twice.
+	//
+	again, err := client.PlaceOrder(ctx, newOrder())
+	if err != nil {
+		t.Fatalf("place order again: %v", err)
+	}
+
+	if again.ID != placed.ID {
+		t.Errorf("expected the same order, got '%s' and '%s'", placed.ID, again.ID)
+	}
+
+	orders := srv.Orders()
+	if len(orders) != 1 {
+		t.Fatalf("expected 1 order, got %d", len(orders))
+	}
+
+	if orders[0].Key != "c0ffee" || orders[0].Amount != 1020 {
+		t.Errorf("unexpected order %+v", orders[0])
+	}
+
+	if requests := srv.Requests(dominostest.EndpointPlaceOrder); requests != 2 {
+		t.Errorf("expected 2 requests, got %d", requests)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	ctx := context.Background()
+	srv := newServer(t)
+	client := newClient(t, srv.URL)
+
+	placed, err := client.PlaceOrder(ctx, newOrder())
+	if err != nil {
+		t.Fatalf("place order: %v", err)
+	}
+
+	tracked, found, err := client.TrackOrderByKey(ctx, phone, "c0ffee")
+	if err != nil {
+		t.Fatalf("track order by key: %v", err)
+	}
+
+	if !found || tracked.OrderID != placed.ID || tracked.Stage != dominos.OrderStageMaking {
+		t.Fatalf("expected order '%s' to be tracked at the making stage, got %+v", placed.ID, tracked)
+	}
+
+	srv.SetOrderStage(placed.ID, dominos.OrderStageComplete)
+
+	tracked, found, err = client.TrackOrder(ctx, phone, placed.ID)
+	if err != nil {
+		t.Fatalf("track order: %v", err)
+	}
+
+	if !found || tracked.Stage != dominos.OrderStageComplete {
+		t.Errorf("expected the order to be complete, got %+v", tracked)
+	}
+
+	if _, found, err := client.TrackOrderByKey(ctx, phone, "decaf"); err != nil || found {
+		t.Errorf("expected no order with an unknown key, got %v (err: %v)", found, err)
+	}
+
+	if orders, err := client.TrackOrders(ctx, "416-555-0100"); err != nil || len(orders) != 0 {
+		t.Errorf("expected no orders for another phone, got %d (err: %v)", len(orders), err)
+	}
+}
+
+func TestFail(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("times", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPriceOrder, dominostest.Failure{
+			StatusCode: http.StatusServiceUnavailable,
+			Times:      2,
+		})
+
+		for idx := 0; idx < 2; idx++ {
+			apiErr := &dominos.APIError{}
+			if _, err := client.PriceOrder(ctx, newOrder()); !errors.As(err, &apiErr) ||
+				apiErr.StatusCode != http.StatusServiceUnavailable {
+				t.Fatalf("expected request %d to fail with a 503, got %v", idx+1, err)
+			}
+		}
+
+		if _, err := client.PriceOrder(ctx, newOrder()); err != nil {
+			t.Fatalf("expected the endpoint to be back to normal, got %v", err)
+		}
+
+		if requests := srv.Requests(dominostest.EndpointPriceOrder); requests != 3 {
+			t.Errorf("expected 3 requests, got %d", requests)
+		}
+	})
+
+	t.Run("code", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+			Code: "StoreClosed",
+		})
+
+		apiErr := &dominos.APIError{}
+		if _, err := client.PlaceOrder(ctx, newOrder()); !errors.As(err, &apiErr) ||
+			apiErr.StatusCode != http.StatusOK || apiErr.Code() != "StoreClosed" {
+			t.Fatalf("expected a 'StoreClosed' status -1, got %v", err)
+		}
+
+		if orders := srv.Orders(); len(orders) != 0 {
+			t.Errorf("expected no orders, got %d", len(orders))
+		}
+	})
+
+	t.Run("after placing", func(t *testing.T) {
+		srv := newServer(t)
+		client := newClient(t, srv.URL)
+
+		srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+			StatusCode:   http.StatusBadGateway,
+			AfterPlacing: true,
+			Times:        1,
+		})
+
+		if _, err := client.PlaceOrder(ctx, newOrder()); err == nil {
+			t.Fatalf("expected placing the order to fail")
+		}
+
+		if orders := srv.Orders(); len(orders) != 1 {
+			t.Fatalf("expected the order to be placed regardless, got %d orders", len(orders))
+		}
+
+		if _, found, err := client.TrackOrderByKey(ctx, phone, "c0ffee"); err != nil || !found {
+			t.Errorf("expected the order to be tracked, got %v (err: %v)", found, err)
+		}
+	})
+}
@@ -0,0 +1,257 @@
+package dominos
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// genericStatusCodes are the codes that Domino's uses in status items to
+// tell the overall outcome of a request, rather than the reason for it.
+var genericStatusCodes = map[string]bool{
+	"Success": true,
+	"Failure": true,
+	"Warning": true,
+}
+
+var invalidReasonChars = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+// APIError is returned when Domino's refuses to fulfill a request, either
+// by replying with a non-2xx status code, or by processing it and replying
+// with `Status: -1`.
+type APIError struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int
+
+	// StatusItems are the codes (e.g., "StoreClosed") that Domino's gave
+	// for not fulfilling the request, if any.
+	StatusItems []StatusItem
+
+	// CorrectiveAction is what Domino's suggests doing for the request to
+	// get fulfilled, if anything.
+	CorrectiveAction *CorrectiveAction
+
+	// Body is the raw body of the response.
+	Body []byte
+}
+
+type StatusItem struct {
+	Code    string
+	Message string
+
+	// Coupon is the code of the coupon (in the order) that the item is
+	// about, if any.
+	Coupon string
+}
+
+type CorrectiveAction struct {
+	Action string
+	Code   string
+	Detail string
+}
+
+func (e *APIError) Error() string {
+	details := e.details()
+
+	if e.StatusCode < 200 || e.StatusCode >= 300 {
+		if details == "" {
+			return fmt.Sprintf("unexpected status: %d %s",
+				e.StatusCode, http.StatusText(e.StatusCode),
+			)
+		}
+
+		return fmt.Sprintf("unexpected status: %d %s: %s",
+			e.StatusCode, http.StatusText(e.StatusCode), details,
+		)
+	}
+
+	return fmt.Sprintf("status -1: %s", details)
+}
+
+// Code is the code that best describes why the request wasn't fulfilled,
+// e.g., "StoreClosed".
+func (e *APIError) Code() string {
+	if e.CorrectiveAction != nil && e.CorrectiveAction.Code != "" {
+		return e.CorrectiveAction.Code
+	}
+
+	for _, item := range e.StatusItems {
+		if !genericStatusCodes[item.Code] {
+			return item.Code
+		}
+	}
+
+	if len(e.StatusItems) != 0 {
+		return e.StatusItems[0].Code
+	}
+
+	return ""
+}
+
+// Reason is the code that best describes why the request wasn't fulfilled,
+// in a form that can be used as the reason of a condition.
+func (e *APIError) Reason() string {
+	reason, ok := conditionReason(e.Code())
+	if !ok {
+		return fmt.Sprintf("HTTPStatus%d", e.StatusCode)
+	}
+
+	return reason
+}
+
+// Message describes, in a human readable form, why the request wasn't
+// fulfilled.
+func (e *APIError) Message() string {
+	if details := e.details(); details != "" {
+		return details
+	}
+
+	return fmt.Sprintf("%d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+func (e *APIError) details() string {
+	parts := []string{}
+	codes := map[string]bool{}
+	seen := map[[2]string]bool{}
+
+	for _, item := range e.StatusItems {
+		key := [2]string{item.Code, item.Coupon}
+		if genericStatusCodes[item.Code] || seen[key] {
+			continue
+		}
+
+		codes[item.Code] = true
+		seen[key] = true
+		parts = append(parts, item.String())
+	}
+
+	if action := e.CorrectiveAction; action != nil {
+		txt := action.Detail
+		if txt == "" {
+			txt = action.Action
+		}
+
+		switch {
+		case txt == "" && codes[action.Code]:
+			// already listed among the status items.
+		case txt == "":
+			txt = action.Code
+		case action.Code != "" && !codes[action.Code]:
+			txt = action.Code + ": " + txt
+		}
+
+		if txt != "" {
+			parts = append(parts, txt)
+		}
+	}
+
+	if len(parts) == 0 {
+		for _, item := range e.StatusItems {
+			parts = append(parts, item.Code)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (i StatusItem) String() string {
+	switch {
+	case i.Message != "" && i.Coupon != "":
+		return i.Code + " (coupon " + i.Coupon + ": " + i.Message + ")"
+	case i.Coupon != "":
+		return i.Code + " (coupon " + i.Coupon + ")"
+	case i.Message != "":
+		return i.Code + " (" + i.Message + ")"
+	}
+
+	return i.Code
+}
+
+// conditionReason turns a code into a form that can be used as the reason
+// of a condition, if it can be.
+func conditionReason(code string) (string, bool) {
+	reason := invalidReasonChars.ReplaceAllString(code, "")
+	if reason == "" || !isLetter(reason[0]) {
+		return "", false
+	}
+
+	return reason, true
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// notSentError wraps the errors of requests that failed before anything
+// got sent to Domino's.
+type notSentError struct {
+	err error
+}
+
+func (e *notSentError) Error() string {
+	return e.err.Error()
+}
+
+func (e *notSentError) Unwrap() error {
+	return e.err
+}
+
+// IsNotSent tells whether a request failed before reaching Domino's, in
+// which case it's certain that it had no effect.
+//
+// Failures that don't satisfy IsNotSent (other than the ones where Domino's
+// explicitly rejected the request) might have happened after Domino's got
+// the request, e.g., while waiting for its response.
+func IsNotSent(err error) bool {
+	var notSent *notSentError
+	return errors.As(err, &notSent)
+}
+
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// IsCanceled tells whether a request failed due to its context having been
+// canceled.
+func IsCanceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// IsTimeout tells whether a request failed due to it not having completed
+// in time.
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// IsRetryable tells whether a request that failed might succeed if tried
+// again, i.e., whether it failed due to a timeout, a network error, or
+// Domino's having trouble on their end, rather than having been rejected
+// or canceled.
+func IsRetryable(err error) bool {
+	if err == nil || IsCanceled(err) {
+		return false
+	}
+
+	if IsTimeout(err) {
+		return true
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode == http.StatusTooManyRequests ||
+			apiErr.StatusCode >= 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
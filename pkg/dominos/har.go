@@ -0,0 +1,162 @@
+package dominos
+
+import (
+	"net/http"
+	"sort"
+	"time"
+)
+
+// The types below are the subset of HTTP Archive (HAR) 1.2 that exchanges
+// with Domino's get captured as, so that they can be inspected with the
+// usual tools (e.g., the network tab of browsers).
+//
+// See http://www.softwareishard.com/blog/har-12-spec/.
+
+type harFile struct {
+	Log harLog `json:"log"`
+}
+
+type harLog struct {
+	Version string     `json:"version"`
+	Creator harCreator `json:"creator"`
+	Entries []harEntry `json:"entries"`
+}
+
+type harCreator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	Cookies     []harNameValue `json:"cookies"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Headers     []harNameValue `json:"headers"`
+	Cookies     []harNameValue `json:"cookies"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+}
+
+type harTimings struct {
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+}
+
+// newHAR assembles the (redacted) HAR capture of an exchange.
+func newHAR(
+	req *http.Request, reqBody []byte,
+	resp *http.Response, respBody []byte,
+	start time.Time, elapsed time.Duration,
+) harFile {
+	millis := float64(elapsed) / float64(time.Millisecond)
+
+	request := harRequest{
+		Method:      req.Method,
+		URL:         redactURL(req.URL),
+		HTTPVersion: req.Proto,
+		Headers:     harHeaders(redactHeaders(req.Header)),
+		QueryString: []harNameValue{},
+		Cookies:     []harNameValue{},
+		HeadersSize: -1,
+		BodySize:    len(reqBody),
+	}
+
+	for name, values := range redactQuery(req.URL) {
+		for _, value := range values {
+			request.QueryString = append(request.QueryString, harNameValue{name, value})
+		}
+	}
+
+	if len(reqBody) > 0 {
+		request.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     redactBody(reqBody),
+		}
+	}
+
+	text := redactBody(respBody)
+
+	return harFile{
+		Log: harLog{
+			Version: "1.2",
+			Creator: harCreator{Name: "pizza-controller", Version: "0"},
+			Entries: []harEntry{
+				{
+					StartedDateTime: start.Format(time.RFC3339Nano),
+					Time:            millis,
+					Request:         request,
+					Response: harResponse{
+						Status:      resp.StatusCode,
+						StatusText:  http.StatusText(resp.StatusCode),
+						HTTPVersion: resp.Proto,
+						Headers:     harHeaders(redactHeaders(resp.Header)),
+						Cookies:     []harNameValue{},
+						Content: harContent{
+							Size:     len(text),
+							MimeType: resp.Header.Get("Content-Type"),
+							Text:     text,
+						},
+						HeadersSize: -1,
+						BodySize:    len(respBody),
+					},
+					Timings: harTimings{Wait: millis},
+				},
+			},
+		},
+	}
+}
+
+func harHeaders(h http.Header) []harNameValue {
+	res := []harNameValue{}
+	for name, values := range h {
+		for _, value := range values {
+			res = append(res, harNameValue{name, value})
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	return res
+}
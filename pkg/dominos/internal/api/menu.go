@@ -6,6 +6,7 @@ type MenuResponse struct {
 		Coupons       MenuCategory
 		Preconfigured MenuCategory `json:"PreconfiguredProducts"`
 	} `json:"Categorization"`
+	Coupons       map[string]*Coupon
 	Products      map[string]*Product
 	Variants      map[string]*Variant
 	Toppings      map[string]map[string]Topping
@@ -42,6 +43,15 @@ type ItemCommon struct {
 	Local bool
 }
 
+// Coupon is a deal offered by a store, e.g., a discount on a combination of
+// products.
+type Coupon struct {
+	ItemCommon
+
+	Description string
+	Price       string
+}
+
 // PreConfiguredProduct is pre-configured product.
 type PreConfiguredProduct struct {
 	ItemCommon
@@ -6,6 +6,7 @@ type OrderMessage struct {
 
 type Order struct {
 	Address       *StreetAddr            `json:"Address"`
+	Coupons       []*OrderCoupon         `json:"Coupons"`
 	CustomerID    string                 `json:",omitempty"` // leave empty
 	Email         string                 `json:"Email"`
 	FirstName     string                 `json:"FirstName"`
@@ -52,6 +53,13 @@ type OrderProduct struct {
 	Opts               map[string]interface{} `json:"Options"`
 }
 
+type OrderCoupon struct {
+	Code  string `json:"Code"`
+	Qty   int    `json:"Qty"`
+	ID    int    `json:"ID"` // index of the coupon within an order
+	IsNew bool   `json:"IsNew"`
+}
+
 // this is the struct that will actually be turning into json an will
 // be sent to dominos.
 type OrderPayment struct {
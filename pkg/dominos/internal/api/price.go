@@ -1,48 +1,40 @@
 package api
 
-import "strings"
+import "encoding/json"
+
+// Amounts is the overall breakdown of the price of an order.
+//
+// Amounts are decoded as `json.Number`s (rather than floats) so that they
+// can be converted to cents without losing precision.
+type Amounts struct {
+	Menu       json.Number `json:"Menu,omitempty"`
+	Discount   json.Number `json:"Discount,omitempty"`
+	Surcharge  json.Number `json:"Surcharge,omitempty"`
+	Adjustment json.Number `json:"Adjustment,omitempty"`
+	Net        json.Number `json:"Net,omitempty"`
+	Tax        json.Number `json:"Tax,omitempty"`
+	Bottle     json.Number `json:"Bottle,omitempty"`
+	Customer   json.Number `json:"Customer,omitempty"`
+	Payment    json.Number `json:"Payment,omitempty"`
+}
 
 type PriceResponse struct {
 	Order struct {
-		Amounts struct {
-			Customer float64 `json:"Customer"`
-		} `json:"Amounts"`
-		CorrectiveAction struct {
-			Action string `json:"Action"`
-			Code   string `json:"Code"`
-			Detail string `json:"Detail"`
-		} `json:"CorrectiveAction"`
+		Amounts          Amounts          `json:"Amounts"`
+		Coupons          []CouponStatus   `json:"Coupons"`
+		StatusItems      StatusItems      `json:"StatusItems"`
+		CorrectiveAction CorrectiveAction `json:"CorrectiveAction"`
 	} `json:"Order"`
 	Status int `json:"Status"`
 }
 
 type PlaceOrderResponse struct {
 	Order struct {
-		OrderID string `json:"OrderID"`
-		Amounts struct {
-			Customer float64 `json:"Customer"`
-		} `json:"Amounts"`
-		EstimatedWaitMinutes string                        `json:"EstimatedWaitMinutes"`
-		StatusItems          PlaceOrderResponseStatusItems `json:"StatusItems"`
+		OrderID              string           `json:"OrderID"`
+		Amounts              Amounts          `json:"Amounts"`
+		EstimatedWaitMinutes string           `json:"EstimatedWaitMinutes"`
+		StatusItems          StatusItems      `json:"StatusItems"`
+		CorrectiveAction     CorrectiveAction `json:"CorrectiveAction"`
 	} `json:"Order"`
 	Status int `json:"Status"`
 }
-
-type PlaceOrderResponseStatusItems []struct {
-	Code      string `json:"Code"`
-	PulseText string `json:"PulseText"`
-}
-
-func (p PlaceOrderResponseStatusItems) String() string {
-	res := []string{}
-	for _, item := range p {
-		txt := item.Code
-		if item.PulseText != "" {
-			txt += " " + item.PulseText
-		}
-
-		res = append(res, txt)
-	}
-
-	return strings.Join(res, ",")
-}
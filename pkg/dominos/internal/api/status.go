@@ -0,0 +1,51 @@
+package api
+
+import "strings"
+
+// Status is the part of the responses from Domino's that tells whether a
+// request has been fulfilled, and why not if it hasn't.
+type Status struct {
+	Status      int         `json:"Status"`
+	StatusItems StatusItems `json:"StatusItems"`
+	Order       struct {
+		StatusItems      StatusItems      `json:"StatusItems"`
+		CorrectiveAction CorrectiveAction `json:"CorrectiveAction"`
+		Coupons          []CouponStatus   `json:"Coupons"`
+	} `json:"Order"`
+}
+
+// CouponStatus tells whether a coupon added to an order could be applied
+// to it, and why not if it couldn't.
+type CouponStatus struct {
+	Code        string      `json:"Code"`
+	Status      int         `json:"Status"`
+	StatusItems StatusItems `json:"StatusItems"`
+}
+
+type StatusItem struct {
+	Code      string `json:"Code"`
+	Message   string `json:"Message"`
+	PulseText string `json:"PulseText"`
+}
+
+type StatusItems []StatusItem
+
+func (p StatusItems) String() string {
+	res := []string{}
+	for _, item := range p {
+		txt := item.Code
+		if item.PulseText != "" {
+			txt += " " + item.PulseText
+		}
+
+		res = append(res, txt)
+	}
+
+	return strings.Join(res, ",")
+}
+
+type CorrectiveAction struct {
+	Action string `json:"Action"`
+	Code   string `json:"Code"`
+	Detail string `json:"Detail"`
+}
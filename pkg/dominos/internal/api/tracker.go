@@ -0,0 +1,29 @@
+package api
+
+import "encoding/xml"
+
+// TrackerResponse is the (SOAP) response of the order tracker, listing the
+// recent orders placed with a given phone number.
+type TrackerResponse struct {
+	XMLName       xml.Name             `xml:"Envelope"`
+	OrderStatuses []TrackerOrderStatus `xml:"Body>GetTrackerDataResponse>OrderStatuses>OrderStatus"`
+}
+
+// TrackerOrderStatus is where an order is at. Times are in the store's
+// local time, e.g., "2020-12-10T18:32:11".
+type TrackerOrderStatus struct {
+	StoreID       string `xml:"StoreID"`
+	OrderID       string `xml:"OrderID"`
+	OrderKey      string `xml:"OrderKey"`
+	Phone         string `xml:"Phone"`
+	ServiceMethod string `xml:"ServiceMethod"`
+	OrderStatus   string `xml:"OrderStatus"`
+	AsOfTime      string `xml:"AsOfTime"`
+	StoreAsOfTime string `xml:"StoreAsOfTime"`
+	StartTime     string `xml:"StartTime"`
+	OvenTime      string `xml:"OvenTime"`
+	RackTime      string `xml:"RackTime"`
+	RouteTime     string `xml:"RouteTime"`
+	DeliveryTime  string `xml:"DeliveryTime"`
+	DriverName    string `xml:"DriverName"`
+}
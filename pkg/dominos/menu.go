@@ -0,0 +1,401 @@
+package dominos
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos/internal/api"
+)
+
+// productTypePizza is the type of the products that support having
+// toppings on only half of it.
+const productTypePizza = "Pizza"
+
+// Menu is the set of items that can be ordered from a store.
+//
+// Products are what one would see as an entry in a menu (e.g., "Hand Tossed
+// Pizza"), while variants are the concrete things that get ordered and priced
+// (e.g., a 12" "Hand Tossed Pizza").
+type Menu struct {
+	Categories    []*MenuCategory
+	Products      []*MenuProduct
+	Variants      []*Variant
+	Toppings      []*MenuOption
+	Sides         []*MenuOption
+	Preconfigured []*Product
+	Coupons       []*Coupon
+
+	products      map[string]*MenuProduct
+	variants      map[string]*Variant
+	preconfigured map[string]*Product
+	coupons       map[string]*Coupon
+}
+
+// MenuCategory groups products (by their codes) and other categories.
+type MenuCategory struct {
+	Code        string
+	Name        string
+	Description string
+	Products    []string
+	Categories  []*MenuCategory
+}
+
+type MenuProduct struct {
+	Code        string
+	Name        string
+	Description string
+	Type        string
+	Variants    []string
+
+	// AvailableToppings maps the codes of the toppings that can go on the
+	// product to the amounts they can be added in (none meaning any).
+	AvailableToppings map[string][]ToppingAmount
+
+	// DefaultToppings maps the codes of the toppings that the product
+	// comes with to their amounts.
+	DefaultToppings map[string]ToppingAmount
+}
+
+type Variant struct {
+	Code        string
+	Name        string
+	ProductCode string
+	Price       string
+}
+
+// Coupon is a deal offered by a store, e.g., a discount on a combination of
+// products.
+type Coupon struct {
+	Code        string
+	Name        string
+	Description string
+	Price       string
+}
+
+// MenuOption is a topping or side that can go with products.
+type MenuOption struct {
+	Code        string
+	Name        string
+	Description string
+	Category    string
+}
+
+// Product retrieves a product by its code.
+func (m *Menu) Product(code string) (*MenuProduct, bool) {
+	product, found := m.products[code]
+	return product, found
+}
+
+// Variant retrieves a variant by its code.
+func (m *Menu) Variant(code string) (*Variant, bool) {
+	variant, found := m.variants[code]
+	return variant, found
+}
+
+// Coupon retrieves a coupon by its code.
+func (m *Menu) Coupon(code string) (*Coupon, bool) {
+	coupon, found := m.coupons[code]
+	return coupon, found
+}
+
+// ValidateCoupons checks that the coupons are offered by the store,
+// according to its menu.
+//
+// Whether a coupon actually applies to an order (e.g., whether the order
+// has the products it requires) is only known once the order is priced
+// (see Price.Coupons).
+func (m *Menu) ValidateCoupons(codes []string) error {
+	seen := map[string]bool{}
+
+	for _, code := range codes {
+		if _, found := m.Coupon(code); !found {
+			return fmt.Errorf("coupon '%s': not found in the store menu", code)
+		}
+
+		if seen[code] {
+			return fmt.Errorf("coupon '%s': can't be used more than once", code)
+		}
+
+		seen[code] = true
+	}
+
+	return nil
+}
+
+// ValidateProducts checks that the products (and the customizations made to
+// them) can be ordered according to the menu.
+func (m *Menu) ValidateProducts(products []Product) error {
+	for _, product := range products {
+		if err := m.validateProduct(product); err != nil {
+			return fmt.Errorf("product '%s': %w", product.ID, err)
+		}
+	}
+
+	return nil
+}
+
+func (m *Menu) validateProduct(product Product) error {
+	if _, found := m.preconfigured[product.ID]; found {
+		if len(product.Toppings) != 0 {
+			return fmt.Errorf("pre-configured products can't be customized")
+		}
+
+		return nil
+	}
+
+	base, found := m.Product(product.ID)
+	if !found {
+		variant, found := m.Variant(product.ID)
+		if !found {
+			return fmt.Errorf("not found in the store menu")
+		}
+
+		base, found = m.Product(variant.ProductCode)
+		if !found {
+			if len(product.Toppings) != 0 {
+				return fmt.Errorf("variant of unknown product '%s' can't be customized",
+					variant.ProductCode,
+				)
+			}
+
+			return nil
+		}
+	}
+
+	for _, topping := range product.Toppings {
+		amounts, found := base.AvailableToppings[topping.Code]
+		if !found {
+			return fmt.Errorf("topping '%s' not available for '%s'",
+				topping.Code, base.Code,
+			)
+		}
+
+		if topping.Amount == ToppingAmountNone {
+			if _, found := base.DefaultToppings[topping.Code]; !found {
+				return fmt.Errorf("topping '%s' can't be removed: not a default topping of '%s'",
+					topping.Code, base.Code,
+				)
+			}
+
+			continue
+		}
+
+		if topping.Amount != "" && len(amounts) != 0 && !containsAmount(amounts, topping.Amount) {
+			return fmt.Errorf("topping '%s' not available in amount %s (available: %s)",
+				topping.Code, topping.Amount, joinAmounts(amounts),
+			)
+		}
+
+		if topping.Coverage != "" && topping.Coverage != CoverageWhole && base.Type != productTypePizza {
+			return fmt.Errorf("topping '%s' can only cover the whole of a '%s'",
+				topping.Code, base.Type,
+			)
+		}
+	}
+
+	return nil
+}
+
+func newMenu(resp *api.MenuResponse) *Menu {
+	menu := &Menu{
+		Categories: []*MenuCategory{
+			newMenuCategory(resp.Categorization.Food),
+			newMenuCategory(resp.Categorization.Preconfigured),
+			newMenuCategory(resp.Categorization.Coupons),
+		},
+		products:      map[string]*MenuProduct{},
+		variants:      map[string]*Variant{},
+		preconfigured: map[string]*Product{},
+		coupons:       map[string]*Coupon{},
+	}
+
+	for _, product := range resp.Products {
+		available := map[string][]ToppingAmount{}
+		for code, amounts := range parseToppings(product.AvailableToppings) {
+			available[code] = toppingAmounts(amounts)
+		}
+
+		defaults := map[string]ToppingAmount{}
+		for code, amounts := range parseToppings(product.DefaultToppings) {
+			defaults[code] = ToppingAmountNormal
+			if len(amounts) != 0 {
+				defaults[code] = ToppingAmount(amounts[0])
+			}
+		}
+
+		menuProduct := &MenuProduct{
+			Code:              product.Code,
+			Name:              product.Name,
+			Description:       product.Description,
+			Type:              product.ProductType,
+			Variants:          product.Variants,
+			AvailableToppings: available,
+			DefaultToppings:   defaults,
+		}
+
+		menu.Products = append(menu.Products, menuProduct)
+		menu.products[product.Code] = menuProduct
+	}
+
+	for _, variant := range resp.Variants {
+		menuVariant := &Variant{
+			Code:        variant.Code,
+			Name:        variant.Name,
+			ProductCode: variant.ProductCode,
+			Price:       variant.Price,
+		}
+
+		menu.Variants = append(menu.Variants, menuVariant)
+		menu.variants[variant.Code] = menuVariant
+	}
+
+	for category, toppings := range resp.Toppings {
+		for _, topping := range toppings {
+			menu.Toppings = append(menu.Toppings, &MenuOption{
+				Code:        topping.Code,
+				Name:        topping.Name,
+				Description: topping.Description,
+				Category:    category,
+			})
+		}
+	}
+
+	for category, sides := range resp.Sides {
+		for _, side := range sides {
+			menu.Sides = append(menu.Sides, &MenuOption{
+				Code:        side.Code,
+				Name:        side.Name,
+				Description: side.Description,
+				Category:    category,
+			})
+		}
+	}
+
+	for _, product := range resp.Preconfigured {
+		preconfigured := &Product{
+			ID:          product.Code,
+			Description: product.Description,
+			Name:        product.Name,
+			Size:        product.Size,
+		}
+
+		menu.Preconfigured = append(menu.Preconfigured, preconfigured)
+		menu.preconfigured[product.Code] = preconfigured
+	}
+
+	for _, coupon := range resp.Coupons {
+		menuCoupon := &Coupon{
+			Code:        coupon.Code,
+			Name:        coupon.Name,
+			Description: coupon.Description,
+			Price:       coupon.Price,
+		}
+
+		menu.Coupons = append(menu.Coupons, menuCoupon)
+		menu.coupons[coupon.Code] = menuCoupon
+	}
+
+	sort.Slice(menu.Products, func(i, j int) bool {
+		return menu.Products[i].Code < menu.Products[j].Code
+	})
+	sort.Slice(menu.Variants, func(i, j int) bool {
+		return menu.Variants[i].Code < menu.Variants[j].Code
+	})
+	sort.Slice(menu.Toppings, func(i, j int) bool {
+		return lessOption(menu.Toppings[i], menu.Toppings[j])
+	})
+	sort.Slice(menu.Sides, func(i, j int) bool {
+		return lessOption(menu.Sides[i], menu.Sides[j])
+	})
+	sort.Slice(menu.Preconfigured, func(i, j int) bool {
+		return menu.Preconfigured[i].ID < menu.Preconfigured[j].ID
+	})
+	sort.Slice(menu.Coupons, func(i, j int) bool {
+		return menu.Coupons[i].Code < menu.Coupons[j].Code
+	})
+
+	return menu
+}
+
+func newMenuCategory(category api.MenuCategory) *MenuCategory {
+	res := &MenuCategory{
+		Code:        category.Code,
+		Name:        category.Name,
+		Description: category.Description,
+		Products:    category.Products,
+	}
+
+	for _, subcategory := range category.Categories {
+		res.Categories = append(res.Categories, newMenuCategory(subcategory))
+	}
+
+	return res
+}
+
+func lessOption(a, b *MenuOption) bool {
+	if a.Category != b.Category {
+		return a.Category < b.Category
+	}
+
+	return a.Code < b.Code
+}
+
+// parseToppings parses the topping lists found in the menu, which look like
+//
+//	X=0:0.5:1:1.5,C=0:0.5:1:1.5,P,H
+//
+// into a map of topping codes to the amounts they're available in (none
+// meaning any amount).
+func parseToppings(list string) map[string][]string {
+	res := map[string][]string{}
+
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.SplitN(entry, "=", 2)
+		if len(parts) == 1 {
+			res[parts[0]] = nil
+			continue
+		}
+
+		res[parts[0]] = strings.Split(parts[1], ":")
+	}
+
+	return res
+}
+
+func toppingAmounts(amounts []string) []ToppingAmount {
+	if len(amounts) == 0 {
+		return nil
+	}
+
+	res := make([]ToppingAmount, len(amounts))
+	for idx, amount := range amounts {
+		res[idx] = ToppingAmount(amount)
+	}
+
+	return res
+}
+
+func containsAmount(amounts []ToppingAmount, amount ToppingAmount) bool {
+	for _, item := range amounts {
+		if item == amount {
+			return true
+		}
+	}
+
+	return false
+}
+
+func joinAmounts(amounts []ToppingAmount) string {
+	res := make([]string, len(amounts))
+	for idx, amount := range amounts {
+		res[idx] = string(amount)
+	}
+
+	return strings.Join(res, ", ")
+}
@@ -0,0 +1,102 @@
+package dominos
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Metrics are the collectors that clients report the requests they make to
+// Domino's to, labelled by endpoint (e.g., "price-order").
+//
+// They're not registered anywhere: that's up to whoever creates them.
+type Metrics struct {
+	// RequestDuration observes how long requests take, regardless of
+	// whether they succeed.
+	RequestDuration *prometheus.HistogramVec
+
+	// RequestErrors counts the requests that failed (see errorCode),
+	// whether with an unsuccessful status code or not.
+	RequestErrors *prometheus.CounterVec
+}
+
+// NewMetrics instantiates the collectors of the requests made to Domino's.
+func NewMetrics() *Metrics {
+	return &Metrics{
+		RequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "dominos_request_duration_seconds",
+			Help:    "How long requests to the Domino's API take, per endpoint.",
+			Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 15, 30},
+		}, []string{"endpoint"}),
+		RequestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "dominos_request_errors_total",
+			Help: "Number of requests to the Domino's API that failed, per endpoint and status code (or reason).",
+		}, []string{"endpoint", "code"}),
+	}
+}
+
+// Collectors lists the collectors, so they can be registered at once.
+func (m *Metrics) Collectors() []prometheus.Collector {
+	return []prometheus.Collector{
+		m.RequestDuration,
+		m.RequestErrors,
+	}
+}
+
+// MetricsTransport reports to a set of Metrics how long the requests that go
+// through it take - whether they failed is up to the client to tell, once
+// it's made sense of the response.
+type MetricsTransport struct {
+	r       http.RoundTripper
+	metrics *Metrics
+}
+
+func (m *MetricsTransport) RoundTrip(h *http.Request) (*http.Response, error) {
+	start := time.Now()
+
+	resp, err := m.r.RoundTrip(h)
+	m.metrics.RequestDuration.WithLabelValues(endpointName(h.URL.Path)).Observe(time.Since(start).Seconds())
+
+	return resp, err
+}
+
+// errorCode labels why a request failed: the status code of the response
+// when unsuccessful, the reason that Domino's gave when rejecting it with a
+// successful one (e.g., "StoreClosed"), and "error" when there's no response
+// that can be made sense of.
+func errorCode(err error) string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return "error"
+	}
+
+	if apiErr.StatusCode < 200 || apiErr.StatusCode >= 300 {
+		return strconv.Itoa(apiErr.StatusCode)
+	}
+
+	return apiErr.Reason()
+}
+
+// endpointName names the endpoint that a request is for, without anything
+// specific to the request (like store IDs) so that the number of label
+// values stays bounded.
+func endpointName(path string) string {
+	switch {
+	case path == PathStoreLocator:
+		return "store-locator"
+	case path == PathPriceOrder:
+		return "price-order"
+	case path == PathPlaceOrder:
+		return "place-order"
+	case path == PathTracker:
+		return "tracker"
+	case strings.HasPrefix(path, "/power/store/") && strings.HasSuffix(path, "/menu"):
+		return "store-menu"
+	}
+
+	return "other"
+}
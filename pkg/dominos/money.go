@@ -0,0 +1,208 @@
+package dominos
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos/internal/api"
+)
+
+// Money is an amount of money in cents (hundredths of the currency), so
+// that adding amounts up and comparing them is exact.
+type Money int64
+
+// ParseMoney parses a decimal amount (e.g., "15.81", "-2", or "3.5"),
+// rounding it to the nearest cent.
+//
+// The amount is parsed digit by digit (rather than as a float) so that no
+// precision is lost along the way.
+func ParseMoney(s string) (Money, error) {
+	str := strings.TrimSpace(s)
+
+	negative := strings.HasPrefix(str, "-")
+	str = strings.TrimPrefix(strings.TrimPrefix(str, "-"), "+")
+
+	units, fraction := str, ""
+	if idx := strings.IndexByte(str, '.'); idx >= 0 {
+		units, fraction = str[:idx], str[idx+1:]
+	}
+
+	if units == "" && fraction == "" {
+		return 0, fmt.Errorf("invalid amount '%s'", s)
+	}
+
+	for _, digits := range []string{units, fraction} {
+		for _, c := range digits {
+			if c < '0' || c > '9' {
+				return 0, fmt.Errorf("invalid amount '%s'", s)
+			}
+		}
+	}
+
+	var cents int64
+	if units != "" {
+		v, err := strconv.ParseInt(units, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid amount '%s': %w", s, err)
+		}
+
+		cents = v * 100
+	}
+
+	fraction += "000"
+	cents += int64(fraction[0]-'0')*10 + int64(fraction[1]-'0')
+	if fraction[2] >= '5' {
+		cents++
+	}
+
+	if negative {
+		cents = -cents
+	}
+
+	return Money(cents), nil
+}
+
+// String formats the amount with exactly two decimal places, e.g., "15.81".
+func (m Money) String() string {
+	sign := ""
+	cents := int64(m)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// Float64 converts the amount to a float, for the places where Domino's
+// expects one (e.g., the amount of a payment).
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// Price is the breakdown of what an order costs.
+type Price struct {
+	// Subtotal is the price of the products, as listed in the menu.
+	Subtotal Money
+
+	// Discounts is how much got taken off of the subtotal (e.g., by
+	// coupons).
+	Discounts Money
+
+	// Fees is what's charged on top of the products, e.g., delivery fees,
+	// surcharges, and bottle deposits.
+	Fees Money
+
+	// Tax is the total of the taxes charged.
+	Tax Money
+
+	// Total is what the customer pays.
+	Total Money
+
+	// Currency is the ISO 4217 code of the currency that the amounts are
+	// in (e.g., "CAD"), if known.
+	Currency string
+
+	// Coupons tells, for each of the coupons added to the order, whether
+	// it got applied.
+	Coupons []CouponStatus
+}
+
+// couponSuccessCodes are the codes that Domino's uses in the status items
+// of a coupon that got applied.
+var couponSuccessCodes = map[string]bool{
+	"CouponFulfilled": true,
+}
+
+// CouponStatus tells whether a coupon added to an order got applied to it.
+type CouponStatus struct {
+	Code string
+
+	// Problems are the reasons (e.g., "CouponNotFulfilled" or
+	// "CouponExpired") why the coupon didn't get applied, if it didn't.
+	Problems []StatusItem
+}
+
+// Applied tells whether the coupon got applied to the order.
+func (c CouponStatus) Applied() bool {
+	return len(c.Problems) == 0
+}
+
+// Reason is the code that best describes why the coupon didn't get
+// applied, in a form that can be used as the reason of a condition.
+func (c CouponStatus) Reason() string {
+	for _, problem := range c.Problems {
+		if reason, ok := conditionReason(problem.Code); ok {
+			return reason
+		}
+	}
+
+	return "CouponNotApplied"
+}
+
+// Message describes, in a human readable form, why the coupon didn't get
+// applied.
+func (c CouponStatus) Message() string {
+	parts := []string{}
+	for _, problem := range c.Problems {
+		if problem.Message != "" {
+			parts = append(parts, problem.Code+" ("+problem.Message+")")
+			continue
+		}
+
+		parts = append(parts, problem.Code)
+	}
+
+	return fmt.Sprintf("coupon '%s' not applied: %s", c.Code, strings.Join(parts, ", "))
+}
+
+// newCouponStatuses converts the status of the coupons of an order, as
+// reported by Domino's.
+func newCouponStatuses(coupons []api.CouponStatus) []CouponStatus {
+	res := []CouponStatus{}
+
+	for _, coupon := range coupons {
+		status := CouponStatus{Code: coupon.Code}
+
+		for _, item := range coupon.StatusItems {
+			if genericStatusCodes[item.Code] || couponSuccessCodes[item.Code] {
+				continue
+			}
+
+			message := item.Message
+			if message == "" {
+				message = item.PulseText
+			}
+
+			status.Problems = append(status.Problems, StatusItem{
+				Code:    item.Code,
+				Message: message,
+				Coupon:  coupon.Code,
+			})
+		}
+
+		if coupon.Status < 0 && len(status.Problems) == 0 {
+			status.Problems = append(status.Problems, StatusItem{
+				Code:   "CouponNotFulfilled",
+				Coupon: coupon.Code,
+			})
+		}
+
+		res = append(res, status)
+	}
+
+	return res
+}
+
+// CurrencyForCountry retrieves the currency that orders placed in a given
+// country are priced in.
+func CurrencyForCountry(country Country) (string, error) {
+	switch country {
+	case CountryCanada:
+		return "CAD", nil
+	case CountryUnitedStates:
+		return "USD", nil
+	}
+
+	return "", fmt.Errorf("unsupported country '%s'", country)
+}
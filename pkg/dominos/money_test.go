@@ -0,0 +1,85 @@
+package dominos
+
+import (
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	for _, tc := range []struct {
+		str      string
+		expected Money
+	}{
+		{"15.81", 1581},
+		{"0", 0},
+		{"-2", -200},
+		{"+4.20", 420},
+		{"3.5", 350},
+		{".99", 99},
+		{"7.", 700},
+		{" 12.00 ", 1200},
+		{"1.005", 101},
+		{"1.0049", 100},
+		{"0.995", 100},
+		{"-0.005", -1},
+		{"19.999999999", 2000},
+		{"123456789.12", 12345678912},
+	} {
+		t.Run(tc.str, func(t *testing.T) {
+			res, err := ParseMoney(tc.str)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if res != tc.expected {
+				t.Errorf("expected %d, got %d", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestParseMoneyInvalid(t *testing.T) {
+	for _, str := range []string{
+		"", " ", "-", ".", "abc", "1.2.3", "1,50", "$15", "1e3", "--1", "1 000",
+	} {
+		t.Run(str, func(t *testing.T) {
+			if res, err := ParseMoney(str); err == nil {
+				t.Errorf("expected an error, got %d", res)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	for _, tc := range []struct {
+		money    Money
+		expected string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{-5, "-0.05"},
+		{1581, "15.81"},
+		{-200, "-2.00"},
+		{100000, "1000.00"},
+	} {
+		t.Run(tc.expected, func(t *testing.T) {
+			if res := tc.money.String(); res != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, res)
+			}
+
+			parsed, err := ParseMoney(tc.money.String())
+			if err != nil {
+				t.Fatalf("parse %q: %v", tc.money.String(), err)
+			}
+
+			if parsed != tc.money {
+				t.Errorf("expected %q to parse back to %d, got %d", tc.money.String(), tc.money, parsed)
+			}
+		})
+	}
+}
+
+func TestMoneyFloat64(t *testing.T) {
+	if res := Money(1581).Float64(); res != 15.81 {
+		t.Errorf("expected 15.81, got %v", res)
+	}
+}
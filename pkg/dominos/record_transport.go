@@ -0,0 +1,174 @@
+package dominos
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Fixture is an exchange with Domino's, as recorded by RecordTransport (and
+// served back by ReplayTransport), with personal information and payment
+// details redacted.
+//
+// Recording real exchanges once (see WithRecording) and replaying them (see
+// WithReplay) allows checking how the responses Domino's gives are decoded,
+// and noticing when their schema changes, without reaching out to it.
+type Fixture struct {
+	Request  FixtureRequest  `json:"request"`
+	Response FixtureResponse `json:"response"`
+}
+
+type FixtureRequest struct {
+	Method string `json:"method"`
+	URL    string `json:"url"`
+	Body   string `json:"body,omitempty"`
+}
+
+type FixtureResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// RecordTransport records the exchanges that go through it as fixtures in a
+// directory, one file per distinct request.
+type RecordTransport struct {
+	r   http.RoundTripper
+	dir string
+}
+
+func (t *RecordTransport) RoundTrip(h *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(h)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	resp, err := t.r.RoundTrip(h)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read response body: %w", err)
+	}
+
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	// the body gets redacted, so its length isn't the one it was sent
+	// with; nor is the date of any relevance when replaying it.
+	//
+	header := redactHeaders(resp.Header)
+	header.Del("Content-Length")
+	header.Del("Date")
+
+	fixture := Fixture{
+		Request: FixtureRequest{
+			Method: h.Method,
+			URL:    redactURL(h.URL),
+			Body:   redactBody(reqBody),
+		},
+		Response: FixtureResponse{
+			Status: resp.StatusCode,
+			Header: header,
+			Body:   redactBody(respBody),
+		},
+	}
+
+	content, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshal fixture: %w", err)
+	}
+
+	if err := os.MkdirAll(t.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("mkdir '%s': %w", t.dir, err)
+	}
+
+	fname := filepath.Join(t.dir, fixtureName(h, reqBody))
+	if err := ioutil.WriteFile(fname, append(content, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("write fixture '%s': %w", fname, err)
+	}
+
+	return resp, nil
+}
+
+// ReplayTransport serves the exchanges recorded by RecordTransport back,
+// never reaching out to Domino's. Requests that weren't recorded fail.
+type ReplayTransport struct {
+	dir string
+}
+
+func (t *ReplayTransport) RoundTrip(h *http.Request) (*http.Response, error) {
+	reqBody, err := requestBody(h)
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	if h.Body != nil {
+		h.Body.Close()
+	}
+
+	fname := filepath.Join(t.dir, fixtureName(h, reqBody))
+	content, err := ioutil.ReadFile(fname)
+	if err != nil {
+		return nil, fmt.Errorf("no fixture for %s '%s': %w",
+			strings.ToLower(h.Method), redactURL(h.URL), err,
+		)
+	}
+
+	fixture := Fixture{}
+	if err := json.Unmarshal(content, &fixture); err != nil {
+		return nil, fmt.Errorf("unmarshal fixture '%s': %w", fname, err)
+	}
+
+	header := fixture.Response.Header
+	if header == nil {
+		header = http.Header{}
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", fixture.Response.Status, http.StatusText(fixture.Response.Status)),
+		StatusCode:    fixture.Response.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(fixture.Response.Body)),
+		ContentLength: int64(len(fixture.Response.Body)),
+		Request:       h,
+	}, nil
+}
+
+// volatilePaths are the JSON paths of the fields of requests that differ
+// every time, even for the same request (e.g., the key of an order).
+var volatilePaths = []string{
+	"Order.OrderID",
+}
+
+// fixtureName names the fixture of a request after the endpoint it's for,
+// and a digest of the request once redacted - so that recording the same
+// request twice overwrites the previous recording, and so that requests
+// differing only in personal information share a fixture.
+//
+// The host isn't part of the digest, so that what got recorded against
+// Domino's can be replayed regardless of where the client points at.
+func fixtureName(h *http.Request, body []byte) string {
+	paths := append(append([]string{}, RedactedPaths...), volatilePaths...)
+
+	digest := sha256.Sum256([]byte(
+		h.Method + " " + h.URL.Path + "?" + redactQuery(h.URL).Encode() + "\n" +
+			redactBodyPaths(body, paths),
+	))
+
+	return fmt.Sprintf("%s-%s-%s.json",
+		endpointName(h.URL.Path), strings.ToLower(h.Method), hex.EncodeToString(digest[:])[:12],
+	)
+}
@@ -0,0 +1,198 @@
+package dominos
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Redacted is what personal information and payment details get replaced
+// with when requests and responses are logged or captured.
+const Redacted = "[REDACTED]"
+
+// RedactedPaths are the JSON paths of the fields (of request and response
+// bodies) that hold personal information or payment details: the address
+// that the store locator echoes back (and the alternatives to it that it
+// suggests), and the customer and payment details of orders.
+//
+// Paths are made of field names separated by dots, with a `[]` suffix
+// standing for every element of an array (e.g., "Order.Payments[].Number").
+var RedactedPaths = []string{
+	"Address.Street",
+	"Address.StreetNumber",
+	"Address.StreetName",
+	"Address.UnitType",
+	"Address.UnitNumber",
+	"Address.PostalCode",
+	"AlternativeAddress[]",
+	"Order.CustomerID",
+	"Order.FirstName",
+	"Order.LastName",
+	"Order.Email",
+	"Order.Phone",
+	"Order.Address.Street",
+	"Order.Address.StreetNumber",
+	"Order.Address.StreetName",
+	"Order.Address.UnitType",
+	"Order.Address.UnitNumber",
+	"Order.Address.PostalCode",
+	"Order.Payments[].Number",
+	"Order.Payments[].Expiration",
+	"Order.Payments[].SecurityCode",
+	"Order.Payments[].PostalCode",
+	"Order.Payments[].CardID",
+	"Order.Payments[].OTP",
+}
+
+// RedactedQueryParams are the query parameters that hold personal
+// information (the address searched for by the store locator, and the phone
+// number searched for by the order tracker).
+var RedactedQueryParams = []string{"s", "c", "Phone"}
+
+// RedactedHeaders are the headers that could carry credentials.
+var RedactedHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// redactedXMLElements are the elements of the order tracker responses that
+// hold personal information.
+var redactedXMLElements = func() []*regexp.Regexp {
+	res := []*regexp.Regexp{}
+	for _, name := range []string{"Phone", "DriverName"} {
+		res = append(res, regexp.MustCompile(`<`+name+`>[^<]*</`+name+`>`))
+	}
+
+	return res
+}()
+
+// redactURL replaces the values of RedactedQueryParams in a URL.
+func redactURL(u *url.URL) string {
+	res := *u
+	res.RawQuery = redactQuery(u).Encode()
+
+	return res.String()
+}
+
+// redactQuery retrieves the query parameters of a URL, with the values of
+// RedactedQueryParams replaced.
+func redactQuery(u *url.URL) url.Values {
+	v := u.Query()
+	for _, param := range RedactedQueryParams {
+		if _, found := v[param]; found {
+			v.Set(param, Redacted)
+		}
+	}
+
+	return v
+}
+
+// redactHeaders copies a set of headers, replacing the values of
+// RedactedHeaders.
+func redactHeaders(h http.Header) http.Header {
+	res := h.Clone()
+	for _, name := range RedactedHeaders {
+		if res.Get(name) != "" {
+			res.Set(name, Redacted)
+		}
+	}
+
+	return res
+}
+
+// redactBody replaces personal information and payment details in a
+// request or response body.
+//
+// JSON bodies have the fields under RedactedPaths replaced, and XML ones the
+// few elements known to hold personal information. Anything else (including
+// JSON that can't be parsed) is left out entirely, as there's no telling
+// what it might contain.
+func redactBody(body []byte) string {
+	return redactBodyPaths(body, RedactedPaths)
+}
+
+// redactBodyPaths is redactBody, replacing the fields under a given set of
+// JSON paths.
+func redactBodyPaths(body []byte, paths []string) string {
+	trimmed := bytes.TrimSpace(body)
+
+	switch {
+	case len(trimmed) == 0:
+		return ""
+	case trimmed[0] == '<':
+		res := string(trimmed)
+		for _, re := range redactedXMLElements {
+			res = re.ReplaceAllStringFunc(res, func(elem string) string {
+				name := elem[1:strings.IndexByte(elem, '>')]
+				return "<" + name + ">" + Redacted + "</" + name + ">"
+			})
+		}
+
+		return res
+	}
+
+	var v interface{}
+	if err := json.Unmarshal(trimmed, &v); err != nil {
+		return Redacted
+	}
+
+	for _, path := range paths {
+		redactPath(v, strings.Split(path, "."))
+	}
+
+	res, err := json.Marshal(v)
+	if err != nil {
+		return Redacted
+	}
+
+	return string(res)
+}
+
+// redactPath replaces the values found under a (split) JSON path.
+func redactPath(v interface{}, path []string) {
+	obj, ok := v.(map[string]interface{})
+	if !ok || len(path) == 0 {
+		return
+	}
+
+	field := strings.TrimSuffix(path[0], "[]")
+	child, found := obj[field]
+	if !found || child == nil {
+		return
+	}
+
+	if field != path[0] {
+		elems, ok := child.([]interface{})
+		if !ok {
+			return
+		}
+
+		for idx := range elems {
+			if len(path) == 1 {
+				elems[idx] = redactValue(elems[idx])
+				continue
+			}
+
+			redactPath(elems[idx], path[1:])
+		}
+
+		return
+	}
+
+	if len(path) == 1 {
+		obj[field] = redactValue(child)
+		return
+	}
+
+	redactPath(child, path[1:])
+}
+
+// redactValue replaces a value, unless it's empty (there being nothing to
+// hide then, and an empty value telling something about the request).
+func redactValue(v interface{}) interface{} {
+	if v == nil || v == "" {
+		return v
+	}
+
+	return Redacted
+}
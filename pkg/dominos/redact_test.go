@@ -0,0 +1,152 @@
+package dominos
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+// storeLocatorResponse is a response from the store locator, as sent by
+// Domino's (trimmed down to a single store).
+const storeLocatorResponse = `{
+  "Status": 0,
+  "Granularity": "Exact",
+  "Address": {
+    "Street": "90 BREMNER BLVD",
+    "StreetNumber": "90",
+    "StreetName": "BREMNER BLVD",
+    "UnitType": "APT",
+    "UnitNumber": "1203",
+    "City": "TORONTO",
+    "Region": "ON",
+    "PostalCode": "M5J 0A9"
+  },
+  "AlternativeAddress": [
+    {"Street": "90 BREMNER BLVD E", "PostalCode": "M5J 0A9"}
+  ],
+  "Stores": [
+    {
+      "StoreID": "10391",
+      "IsDeliveryStore": true,
+      "Phone": "416-555-0101",
+      "AddressDescription": "150 Front St W\nToronto, ON M5J 2N1",
+      "IsOpen": true,
+      "ServiceIsOpen": {"Carryout": true, "Delivery": true, "DriveUpCarryout": false}
+    }
+  ]
+}`
+
+func TestRedactBodyStoreLocatorResponse(t *testing.T) {
+	redacted := redactBody([]byte(storeLocatorResponse))
+
+	for _, secret := range []string{"BREMNER", "1203", "APT", "M5J 0A9"} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted body still contains %q: %s", secret, redacted)
+		}
+	}
+
+	res := struct {
+		Granularity string
+		Address     map[string]string
+		Stores      []struct {
+			StoreID            string
+			AddressDescription string
+		}
+	}{}
+	if err := json.Unmarshal([]byte(redacted), &res); err != nil {
+		t.Fatalf("unmarshal redacted body: %v", err)
+	}
+
+	// what isn't personal information is left as is, so that the
+	// response can still be decoded (and replayed).
+	//
+	if res.Granularity != "Exact" {
+		t.Errorf("granularity: expected %q, got %q", "Exact", res.Granularity)
+	}
+
+	if res.Address["City"] != "TORONTO" || res.Address["Region"] != "ON" {
+		t.Errorf("city and region: expected them to be kept, got %v", res.Address)
+	}
+
+	if res.Address["PostalCode"] != Redacted {
+		t.Errorf("postal code: expected %q, got %q", Redacted, res.Address["PostalCode"])
+	}
+
+	if len(res.Stores) != 1 || res.Stores[0].StoreID != "10391" {
+		t.Errorf("stores: expected store 10391 to be kept, got %+v", res.Stores)
+	}
+}
+
+func TestRedactBodyOrder(t *testing.T) {
+	redacted := redactBody([]byte(`{
+	  "Order": {
+	    "StoreID": "10391",
+	    "FirstName": "barack",
+	    "Email": "barack@example.com",
+	    "Phone": "4165550199",
+	    "Address": {"Street": "90 BREMNER BLVD", "City": "TORONTO", "PostalCode": "M5J 0A9"},
+	    "Payments": [
+	      {"Type": "CreditCard", "Number": "4111111111111111", "SecurityCode": "123", "OTP": ""}
+	    ]
+	  }
+	}`))
+
+	for _, secret := range []string{"barack", "4165550199", "BREMNER", "M5J 0A9", "4111111111111111", `"123"`} {
+		if strings.Contains(redacted, secret) {
+			t.Errorf("redacted body still contains %q: %s", secret, redacted)
+		}
+	}
+
+	for _, kept := range []string{`"StoreID":"10391"`, `"City":"TORONTO"`, `"Type":"CreditCard"`, `"OTP":""`} {
+		if !strings.Contains(redacted, kept) {
+			t.Errorf("redacted body doesn't contain %s: %s", kept, redacted)
+		}
+	}
+}
+
+func TestRedactBody(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		body     string
+		expected string
+	}{
+		{
+			name:     "empty",
+			body:     "  ",
+			expected: "",
+		},
+		{
+			name:     "not json",
+			body:     "Phone: 4165550199",
+			expected: Redacted,
+		},
+		{
+			name:     "xml",
+			body:     "<OrderStatus><OrderID>1</OrderID><Phone>4165550199</Phone><DriverName>Joe</DriverName></OrderStatus>",
+			expected: "<OrderStatus><OrderID>1</OrderID><Phone>" + Redacted + "</Phone><DriverName>" + Redacted + "</DriverName></OrderStatus>",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			if res := redactBody([]byte(tc.body)); res != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestRedactURL(t *testing.T) {
+	u, err := url.Parse("https://order.dominos.ca/power/store-locator?s=90+Bremner+Blvd&c=Toronto%2C+ON+M5J+0A9&type=Delivery")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	redacted := redactURL(u)
+	if strings.Contains(redacted, "Bremner") || strings.Contains(redacted, "Toronto") {
+		t.Errorf("redacted url still contains the address: %s", redacted)
+	}
+
+	if !strings.Contains(redacted, "type=Delivery") {
+		t.Errorf("redacted url doesn't contain the service method: %s", redacted)
+	}
+}
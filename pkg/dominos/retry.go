@@ -0,0 +1,72 @@
+package dominos
+
+import (
+	"math/rand"
+	"time"
+)
+
+// RetryPolicy decides whether (and when) a request that failed should be
+// tried again.
+//
+// It's only ever consulted for requests that are safe to be repeated (i.e.,
+// never for placing orders), and that failed in a way that might not happen
+// again (see IsRetryable).
+type RetryPolicy interface {
+	// Backoff tells how long to wait before making attempt number
+	// `attempt + 1`, given that attempt number `attempt` (starting at 1)
+	// failed with `err`, or false if it shouldn't be retried at all.
+	Backoff(attempt int, err error) (time.Duration, bool)
+}
+
+// DefaultRetryPolicy is the retry policy used unless configured otherwise.
+var DefaultRetryPolicy RetryPolicy = ExponentialBackoff{
+	MaxAttempts: 3,
+	BaseDelay:   500 * time.Millisecond,
+	MaxDelay:    5 * time.Second,
+}
+
+// NoRetries is a retry policy that never retries.
+var NoRetries RetryPolicy = ExponentialBackoff{
+	MaxAttempts: 1,
+}
+
+// ExponentialBackoff retries up to a number of attempts, waiting a random
+// amount of time (up to an exponentially growing limit) between them, so
+// that clients failing at the same time don't all retry at the same time.
+type ExponentialBackoff struct {
+	// MaxAttempts is the maximum number of attempts made, including the
+	// first one.
+	MaxAttempts int
+
+	// BaseDelay is the limit of how long to wait after the first attempt,
+	// doubled for each attempt after it.
+	BaseDelay time.Duration
+
+	// MaxDelay caps how long to wait between any two attempts.
+	MaxDelay time.Duration
+}
+
+func (b ExponentialBackoff) Backoff(attempt int, err error) (time.Duration, bool) {
+	if attempt >= b.MaxAttempts {
+		return 0, false
+	}
+
+	limit := b.BaseDelay
+	for i := 1; i < attempt; i++ {
+		if b.MaxDelay > 0 && limit >= b.MaxDelay {
+			break
+		}
+
+		limit *= 2
+	}
+
+	if b.MaxDelay > 0 && limit > b.MaxDelay {
+		limit = b.MaxDelay
+	}
+
+	if limit <= 0 {
+		return 0, true
+	}
+
+	return time.Duration(rand.Int63n(int64(limit))), true
+}
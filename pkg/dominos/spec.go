@@ -0,0 +1,103 @@
+package dominos
+
+import (
+	"fmt"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+)
+
+// OrderServiceMethod retrieves the service method of an order, defaulting to
+// carryout.
+func OrderServiceMethod(order *v1alpha1.PizzaOrder) Service {
+	if order.Spec.ServiceMethod == "" {
+		return ServiceCarryout
+	}
+
+	return Service(order.Spec.ServiceMethod)
+}
+
+// OrderPaymentType retrieves the payment type of an order, defaulting to
+// paying with a credit card at the door.
+func OrderPaymentType(order *v1alpha1.PizzaOrder) PaymentType {
+	if order.Spec.PaymentType == "" {
+		return PaymentTypeDoorCredit
+	}
+
+	return PaymentType(order.Spec.PaymentType)
+}
+
+// AssembleToppings converts the toppings of a product of an order (and the
+// ones to be removed from it) to the form expected by the client.
+func AssembleToppings(product v1alpha1.PizzaOrderProduct) ([]Topping, error) {
+	toppings := []Topping{}
+
+	for _, topping := range product.Toppings {
+		coverage, found := toppingCoverages[topping.Coverage]
+		if !found {
+			return nil, fmt.Errorf("topping '%s': unknown coverage '%s'",
+				topping.Code, topping.Coverage,
+			)
+		}
+
+		amount, found := toppingAmountNames[topping.Amount]
+		if !found {
+			return nil, fmt.Errorf("topping '%s': unknown amount '%s'",
+				topping.Code, topping.Amount,
+			)
+		}
+
+		toppings = append(toppings, Topping{
+			Code:     topping.Code,
+			Coverage: coverage,
+			Amount:   amount,
+		})
+	}
+
+	for _, code := range product.RemoveToppings {
+		toppings = append(toppings, Topping{
+			Code:     code,
+			Coverage: CoverageWhole,
+			Amount:   ToppingAmountNone,
+		})
+	}
+
+	return toppings, nil
+}
+
+var (
+	toppingCoverages = map[string]Coverage{
+		"":      CoverageWhole,
+		"Whole": CoverageWhole,
+		"Left":  CoverageLeft,
+		"Right": CoverageRight,
+	}
+
+	toppingAmountNames = map[string]ToppingAmount{
+		"":       ToppingAmountNormal,
+		"Light":  ToppingAmountLight,
+		"Normal": ToppingAmountNormal,
+		"Extra":  ToppingAmountExtra,
+		"Double": ToppingAmountDouble,
+	}
+)
+
+// AssemblePriceStatus converts the price of an order to the form it's kept
+// in the status of a PizzaOrder.
+func AssemblePriceStatus(price *Price) *v1alpha1.PizzaOrderPrice {
+	status := &v1alpha1.PizzaOrderPrice{
+		Subtotal: price.Subtotal.String(),
+		Tax:      price.Tax.String(),
+		Total:    price.Total.String(),
+		Currency: price.Currency,
+	}
+
+	if price.Discounts != 0 {
+		status.Discounts = price.Discounts.String()
+	}
+
+	if price.Fees != 0 {
+		status.Fees = price.Fees.String()
+	}
+
+	return status
+}
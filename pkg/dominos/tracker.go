@@ -0,0 +1,184 @@
+package dominos
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos/internal/api"
+)
+
+const (
+	// UnitedStatesTrackerURL is the base URL of the order tracker for
+	// orders placed in the US (for Canada, it's the same as CanadaURL).
+	UnitedStatesTrackerURL = "https://trkweb.dominos.com"
+
+	PathTracker = "/orderstorage/GetTrackerData"
+)
+
+// OrderStage is the stage that an order is at in the store, as reported by
+// the order tracker.
+type OrderStage string
+
+const (
+	OrderStageMaking         OrderStage = "Makeline"
+	OrderStageOven           OrderStage = "Oven"
+	OrderStageQualityCheck   OrderStage = "Routing Station"
+	OrderStageOutForDelivery OrderStage = "Out The Door"
+	OrderStageComplete       OrderStage = "Complete"
+)
+
+// OrderStages are the stages that orders go through, in order. Orders that
+// aren't delivered skip OrderStageOutForDelivery.
+var OrderStages = []OrderStage{
+	OrderStageMaking,
+	OrderStageOven,
+	OrderStageQualityCheck,
+	OrderStageOutForDelivery,
+	OrderStageComplete,
+}
+
+// TrackedOrder is where an order is at, according to the order tracker.
+type TrackedOrder struct {
+	OrderID string
+
+	// OrderKey is the key that the order got placed with (see Order.Key).
+	OrderKey string
+
+	StoreID       string
+	ServiceMethod Service
+
+	// Stage is the stage the order is at, which might not be one of
+	// OrderStages (e.g., for orders that got voided).
+	Stage OrderStage
+
+	// StageTimes are the times at which the order reached each stage, in
+	// the store's local time (e.g., "2020-12-10T18:32:11").
+	StageTimes map[OrderStage]string
+}
+
+// TrackOrders retrieves where the recent orders placed with a phone number
+// are at.
+func (c *Client) TrackOrders(ctx context.Context, phone string) ([]*TrackedOrder, error) {
+	url := *c.trackerHost
+	url.Path = PathTracker
+
+	v := url.Query()
+	v.Set("Phone", digits(phone))
+
+	url.RawQuery = v.Encode()
+
+	body := api.TrackerResponse{}
+	if err := c.do(ctx, http.MethodGet, &url, nil, xmlResponse{&body}); err != nil {
+		return nil, err
+	}
+
+	orders := []*TrackedOrder{}
+	for _, status := range body.OrderStatuses {
+		order := &TrackedOrder{
+			OrderID:       status.OrderID,
+			OrderKey:      status.OrderKey,
+			StoreID:       status.StoreID,
+			ServiceMethod: Service(status.ServiceMethod),
+			Stage:         parseOrderStage(status.OrderStatus),
+			StageTimes:    map[OrderStage]string{},
+		}
+
+		for stage, at := range map[OrderStage]string{
+			OrderStageMaking:         status.StartTime,
+			OrderStageOven:           status.OvenTime,
+			OrderStageQualityCheck:   status.RackTime,
+			OrderStageOutForDelivery: status.RouteTime,
+			OrderStageComplete:       status.DeliveryTime,
+		} {
+			if at != "" {
+				order.StageTimes[stage] = at
+			}
+		}
+
+		orders = append(orders, order)
+	}
+
+	return orders, nil
+}
+
+// TrackOrder retrieves where an order placed with a phone number is at,
+// reporting false if the tracker doesn't know about it (yet, or anymore).
+func (c *Client) TrackOrder(ctx context.Context, phone, orderID string) (*TrackedOrder, bool, error) {
+	orders, err := c.TrackOrders(ctx, phone)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, order := range orders {
+		if order.OrderID == orderID {
+			return order, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// TrackOrderByKey retrieves where an order placed with a phone number is at
+// by the key it got placed with, reporting false if the tracker doesn't know
+// about it.
+//
+// Not finding an order doesn't prove that it wasn't placed: that the tracker
+// reports the keys that orders got placed with has only been seen with
+// dominostest, not with Domino's.
+func (c *Client) TrackOrderByKey(ctx context.Context, phone, orderKey string) (*TrackedOrder, bool, error) {
+	orders, err := c.TrackOrders(ctx, phone)
+	if err != nil {
+		return nil, false, err
+	}
+
+	for _, order := range orders {
+		if order.OrderKey != "" && order.OrderKey == orderKey {
+			return order, true, nil
+		}
+	}
+
+	return nil, false, nil
+}
+
+// trackerURL retrieves the base URL of the order tracker that goes along
+// with an API base URL.
+func trackerURL(host *url.URL) (*url.URL, error) {
+	if strings.TrimSuffix(host.String(), "/") != UnitedStatesURL {
+		return host, nil
+	}
+
+	u, err := url.Parse(UnitedStatesTrackerURL)
+	if err != nil {
+		return nil, fmt.Errorf("url parse '%s': %w", UnitedStatesTrackerURL, err)
+	}
+
+	return u, nil
+}
+
+// parseOrderStage parses the stage reported by the tracker, regardless of
+// how it's capitalized.
+func parseOrderStage(str string) OrderStage {
+	str = strings.TrimSpace(str)
+
+	for _, stage := range OrderStages {
+		if strings.EqualFold(str, string(stage)) {
+			return stage
+		}
+	}
+
+	return OrderStage(str)
+}
+
+func digits(str string) string {
+	res := strings.Builder{}
+	for _, c := range str {
+		if c >= '0' && c <= '9' {
+			res.WriteRune(c)
+		}
+	}
+
+	return res.String()
+}
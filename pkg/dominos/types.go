@@ -1,10 +1,13 @@
 package dominos
 
+import "fmt"
+
 type Service string
 
 const (
-	ServiceDelivery Service = "Delivery"
-	ServiceCarryout Service = "Carryout"
+	ServiceDelivery        Service = "Delivery"
+	ServiceCarryout        Service = "Carryout"
+	ServiceDriveUpCarryout Service = "DriveUpCarryout"
 )
 
 type CreditCardType string
@@ -23,6 +26,31 @@ type CreditCard struct {
 	SecurityCode string
 }
 
+type GiftCard struct {
+	Number string
+	PIN    string
+}
+
+// PaymentType is how an order gets paid for.
+type PaymentType string
+
+const (
+	// PaymentTypeCash is paying with cash at the door (or at the store).
+	PaymentTypeCash PaymentType = "Cash"
+
+	// PaymentTypeDoorCredit is paying with a credit card at the door (or at
+	// the store).
+	PaymentTypeDoorCredit PaymentType = "DoorCredit"
+
+	// PaymentTypeCreditCard is paying online with a credit card, when the
+	// order is placed.
+	PaymentTypeCreditCard PaymentType = "CreditCard"
+
+	// PaymentTypeGiftCard is paying online with a Domino's gift card, when
+	// the order is placed.
+	PaymentTypeGiftCard PaymentType = "GiftCard"
+)
+
 type Address struct {
 	StreetName   string
 	StreetNumber string
@@ -35,13 +63,69 @@ type Store struct {
 	ID      string
 	Phone   string
 	Address string
+
+	// Services are the service methods that the store takes orders for.
+	Services []Service
+
+	// PaymentTypes are the ways that the store accepts orders to be paid
+	// for.
+	PaymentTypes []PaymentType
+
+	// Language is the language that the menu of the store is best
+	// retrieved in (see LanguageForStore).
+	Language string
+
+	// Open tells whether the store is open for the service method it got
+	// located for (see Client.LocateStores).
+	Open bool
 }
 
+// MaxProductQuantity is the largest number of units of a single product that
+// can be ordered at once.
+const MaxProductQuantity = 25
+
 type Product struct {
 	ID          string
 	Description string
 	Name        string
 	Size        string
+
+	// Quantity is the number of units of the product to order. When not set,
+	// a single unit is ordered.
+	Quantity int
+
+	// Toppings are the changes to be made to the product's default
+	// toppings.
+	Toppings []Topping
+}
+
+// Coverage is the portion of a pizza that a topping is spread over.
+type Coverage string
+
+const (
+	CoverageWhole Coverage = "1/1"
+	CoverageLeft  Coverage = "1/2"
+	CoverageRight Coverage = "2/2"
+)
+
+// ToppingAmount is how much of a topping to put on a product.
+type ToppingAmount string
+
+const (
+	ToppingAmountNone   ToppingAmount = "0"
+	ToppingAmountLight  ToppingAmount = "0.5"
+	ToppingAmountNormal ToppingAmount = "1"
+	ToppingAmountExtra  ToppingAmount = "1.5"
+	ToppingAmountDouble ToppingAmount = "2"
+)
+
+// Topping is a change to a product's toppings - either adding one, changing
+// the amount of one that's there by default, or removing it (with
+// ToppingAmountNone).
+type Topping struct {
+	Code     string
+	Coverage Coverage
+	Amount   ToppingAmount
 }
 
 type PersonalInformation struct {
@@ -56,7 +140,65 @@ type Order struct {
 	PersonalInformation PersonalInformation
 	Address             Address
 	Products            []Product
-	CreditCard          CreditCard
-	Service             Service
-	Amount              float64
+
+	// Coupons are the codes of the coupons (see Menu.Coupons) to apply to
+	// the order.
+	Coupons []string
+
+	PaymentType PaymentType
+	CreditCard  CreditCard
+	GiftCard    GiftCard
+	Service     Service
+
+	// Amount is how much the order is paid for (i.e., the total it got
+	// priced at).
+	Amount Money
+
+	// Key, if set, is a client-generated identifier of the order, sent
+	// along when placing it so that an attempt at placing the same order
+	// more than once can be told apart from placing a new one.
+	Key string
+}
+
+// Validate checks whether the order is well-formed before submitting it to
+// Domino's.
+func (o Order) Validate() error {
+	if len(o.Products) == 0 {
+		return fmt.Errorf("no products")
+	}
+
+	for _, product := range o.Products {
+		if product.Quantity < 0 || product.Quantity > MaxProductQuantity {
+			return fmt.Errorf("product '%s': quantity %d not within [1, %d]",
+				product.ID, product.Quantity, MaxProductQuantity,
+			)
+		}
+	}
+
+	switch o.PaymentType {
+	case "", PaymentTypeCash:
+	case PaymentTypeDoorCredit:
+		if o.CreditCard.Type == "" {
+			return fmt.Errorf("door credit payment: missing card type")
+		}
+	case PaymentTypeCreditCard:
+		if o.CreditCard.Type == "" || o.CreditCard.Number == "" || o.CreditCard.Expiration == "" {
+			return fmt.Errorf("credit card payment: missing card type, number, or expiration")
+		}
+	case PaymentTypeGiftCard:
+		if o.GiftCard.Number == "" {
+			return fmt.Errorf("gift card payment: missing card number")
+		}
+	default:
+		return fmt.Errorf("unknown payment type '%s'", o.PaymentType)
+	}
+
+	return nil
 }
+
+type Country string
+
+const (
+	CountryCanada       Country = "CA"
+	CountryUnitedStates Country = "US"
+)
@@ -0,0 +1,275 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+// CheckBudget checks whether an order can be placed without going over the
+// maximum price for it, nor over any of the budgets of its namespace, keeping
+// its `BudgetExceeded` condition up to date.
+func (r *PizzaOrderReconciler) CheckBudget(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+) (bool, error) {
+	cond, limited, err := r.BudgetCondition(ctx, order)
+	if err != nil {
+		return false, err
+	}
+
+	within := cond.Status == metav1.ConditionFalse
+	existing := meta.FindStatusCondition(order.Status.Conditions, "BudgetExceeded")
+
+	switch {
+	case !limited && existing == nil:
+		return true, nil
+	case !limited:
+		meta.RemoveStatusCondition(&order.Status.Conditions, "BudgetExceeded")
+	case existing != nil && existing.Status == cond.Status && existing.Message == cond.Message:
+		return within, nil
+	default:
+		meta.SetStatusCondition(&order.Status.Conditions, cond)
+	}
+
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return false, fmt.Errorf("budget status update: %w", err)
+	}
+
+	if limited && !within {
+		r.Recorder.Event(order, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	}
+
+	return within, nil
+}
+
+// BudgetCondition assesses whether placing an order would go over the
+// maximum price for it, or over any of the budgets of its namespace,
+// reporting false if there's no limit to go over at all.
+//
+// Budgets, policies and orders are read uncached, so that two orders placed
+// one right after the other can't both fit in what's left of a budget.
+func (r *PizzaOrderReconciler) BudgetCondition(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+) (metav1.Condition, bool, error) {
+	cond := metav1.Condition{
+		Type:               "BudgetExceeded",
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinBudget",
+		ObservedGeneration: order.Generation,
+	}
+
+	price, err := OrderTotal(order)
+	if err != nil {
+		return cond, false, fmt.Errorf("order total: %w", err)
+	}
+
+	maxPrice, limited, err := r.MaxPrice(ctx, order)
+	if err != nil {
+		return cond, false, fmt.Errorf("max price: %w", err)
+	}
+
+	if limited {
+		cond.Message = fmt.Sprintf("price %s is within the maximum of %s", price, maxPrice)
+
+		if price > maxPrice {
+			cond.Status = metav1.ConditionTrue
+			cond.Reason = "MaxPriceExceeded"
+			cond.Message = fmt.Sprintf("price %s exceeds the maximum of %s", price, maxPrice)
+
+			return cond, true, nil
+		}
+	}
+
+	budgets := &v1alpha1.PizzaBudgetList{}
+	if err := r.APIReader.List(ctx, budgets, client.InNamespace(order.Namespace)); err != nil {
+		return cond, false, fmt.Errorf("list budgets: %w", err)
+	}
+
+	if len(budgets.Items) == 0 {
+		return cond, limited, nil
+	}
+
+	orders := &v1alpha1.PizzaOrderList{}
+	if err := r.APIReader.List(ctx, orders, client.InNamespace(order.Namespace)); err != nil {
+		return cond, false, fmt.Errorf("list orders: %w", err)
+	}
+
+	now := time.Now()
+	applicable := false
+	for _, budget := range budgets.Items {
+		if !InBudgetCurrency(order, budget.Spec.Currency) {
+			continue
+		}
+
+		applicable = true
+
+		amount, err := dominos.ParseMoney(budget.Spec.Amount)
+		if err != nil {
+			return cond, false, fmt.Errorf("budget '%s': %w", budget.Name, err)
+		}
+
+		start := BudgetPeriodStart(budget.Spec.Period, now)
+		consumed := BudgetConsumption(orders.Items, budget.Spec.Currency, start, order.UID)
+
+		if consumed+price > amount {
+			cond.Status = metav1.ConditionTrue
+			cond.Reason = "BudgetExhausted"
+			cond.Message = fmt.Sprintf("price %s would bring budget '%s' to %s, over its %s amount of %s",
+				price, budget.Name, consumed+price, budgetPeriod(budget.Spec.Period), amount,
+			)
+
+			return cond, true, nil
+		}
+	}
+
+	if !applicable {
+		return cond, limited, nil
+	}
+
+	cond.Message = fmt.Sprintf("price %s is within the maximum price and budgets", price)
+	return cond, true, nil
+}
+
+// MaxPrice determines the maximum price that an order can be placed for:
+// the lowest of the one in its spec and the ones set by the policies in its
+// namespace, if any.
+func (r *PizzaOrderReconciler) MaxPrice(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+) (dominos.Money, bool, error) {
+	policies := &v1alpha1.PizzaPolicyList{}
+	if err := r.APIReader.List(ctx, policies, client.InNamespace(order.Namespace)); err != nil {
+		return 0, false, fmt.Errorf("list policies: %w", err)
+	}
+
+	var (
+		maxPrice dominos.Money
+		limited  bool
+	)
+
+	limit := func(str, source string) error {
+		if str == "" {
+			return nil
+		}
+
+		value, err := dominos.ParseMoney(str)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+
+		if !limited || value < maxPrice {
+			maxPrice, limited = value, true
+		}
+
+		return nil
+	}
+
+	if err := limit(order.Spec.MaxPrice, "spec.maxPrice"); err != nil {
+		return 0, false, err
+	}
+
+	for _, policy := range policies.Items {
+		if err := limit(policy.Spec.MaxOrderPrice, "policy '"+policy.Name+"'"); err != nil {
+			return 0, false, err
+		}
+	}
+
+	return maxPrice, limited, nil
+}
+
+// BudgetConsumption adds up the prices of the orders in `currency` (see
+// InBudgetCurrency) that have been placed (or might have been) since
+// `since`, other than the one identified by `exclude`.
+func BudgetConsumption(
+	orders []v1alpha1.PizzaOrder,
+	currency string,
+	since time.Time,
+	exclude types.UID,
+) dominos.Money {
+	var consumed dominos.Money
+
+	for idx := range orders {
+		order := &orders[idx]
+		if order.UID == exclude || !InBudgetCurrency(order, currency) {
+			continue
+		}
+
+		placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+		if placed == nil || placed.Status == metav1.ConditionFalse {
+			continue
+		}
+
+		if placed.LastTransitionTime.Time.Before(since) {
+			continue
+		}
+
+		price, err := OrderTotal(order)
+		if err != nil {
+			continue
+		}
+
+		consumed += price
+	}
+
+	return consumed
+}
+
+// InBudgetCurrency tells whether an order counts towards a budget in a
+// given currency: budgets without a currency take all orders, and orders
+// whose currency isn't known count towards all budgets.
+func InBudgetCurrency(order *v1alpha1.PizzaOrder, currency string) bool {
+	if currency == "" || order.Status.Price == nil || order.Status.Price.Currency == "" {
+		return true
+	}
+
+	return order.Status.Price.Currency == currency
+}
+
+// BudgetPeriodStart determines when the period of a budget that `now` falls
+// in started.
+func BudgetPeriodStart(period string, now time.Time) time.Time {
+	now = now.UTC()
+	midnight := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch budgetPeriod(period) {
+	case "Daily":
+		return midnight
+	case "Weekly":
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return midnight.AddDate(0, 0, -daysSinceMonday)
+	default:
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// BudgetPeriodEnd determines when the period of a budget that started at
+// `start` ends.
+func BudgetPeriodEnd(period string, start time.Time) time.Time {
+	switch budgetPeriod(period) {
+	case "Daily":
+		return start.AddDate(0, 0, 1)
+	case "Weekly":
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
+
+func budgetPeriod(period string) string {
+	if period == "" {
+		return "Monthly"
+	}
+
+	return period
+}
@@ -0,0 +1,118 @@
+package reconciler_test
+
+import (
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/reconciler"
+)
+
+// budgetOrder is an order priced at `total` in `currency` whose OrderPlaced
+// condition (if `placed` isn't empty) transitioned to `placed` at `at`.
+func budgetOrder(uid, total, currency string, placed metav1.ConditionStatus, at time.Time) v1alpha1.PizzaOrder {
+	order := v1alpha1.PizzaOrder{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: uid,
+			UID:  types.UID(uid),
+		},
+		Status: v1alpha1.PizzaOrderStatus{
+			Price: &v1alpha1.PizzaOrderPrice{
+				Total:    total,
+				Currency: currency,
+			},
+		},
+	}
+
+	if placed != "" {
+		order.Status.Conditions = []metav1.Condition{{
+			Type:               "OrderPlaced",
+			Status:             placed,
+			Reason:             "Test",
+			LastTransitionTime: metav1.NewTime(at),
+		}}
+	}
+
+	return order
+}
+
+func TestBudgetConsumption(t *testing.T) {
+	since := time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC)
+	during, before := since.Add(time.Hour), since.Add(-time.Hour)
+
+	orders := []v1alpha1.PizzaOrder{
+		budgetOrder("placed", "10.20", "CAD", metav1.ConditionTrue, during),
+		budgetOrder("unconfirmed", "5.00", "CAD", metav1.ConditionUnknown, during),
+		budgetOrder("unknown-currency", "1.00", "", metav1.ConditionTrue, during),
+		budgetOrder("failed", "100.00", "CAD", metav1.ConditionFalse, during),
+		budgetOrder("not-placed", "100.00", "CAD", "", during),
+		budgetOrder("previous-period", "100.00", "CAD", metav1.ConditionTrue, before),
+		budgetOrder("other-currency", "100.00", "USD", metav1.ConditionTrue, during),
+		budgetOrder("excluded", "100.00", "CAD", metav1.ConditionTrue, during),
+	}
+
+	for _, tc := range []struct {
+		name     string
+		currency string
+		expected dominos.Money
+	}{
+		{"in currency", "CAD", 1620},
+		{"in all currencies", "", 11620},
+		{"in another currency", "USD", 10100},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res := reconciler.BudgetConsumption(orders, tc.currency, since, "excluded")
+			if res != tc.expected {
+				t.Errorf("expected %s, got %s", tc.expected, res)
+			}
+		})
+	}
+}
+
+func TestBudgetPeriodStart(t *testing.T) {
+	// a wednesday.
+	now := time.Date(2020, time.December, 9, 18, 30, 0, 0, time.FixedZone("EST", -5*60*60))
+
+	for _, tc := range []struct {
+		period        string
+		expectedStart time.Time
+		expectedEnd   time.Time
+	}{
+		{
+			period:        "Daily",
+			expectedStart: time.Date(2020, time.December, 9, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2020, time.December, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			period:        "Weekly",
+			expectedStart: time.Date(2020, time.December, 7, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2020, time.December, 14, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			period:        "Monthly",
+			expectedStart: time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			period:        "",
+			expectedStart: time.Date(2020, time.December, 1, 0, 0, 0, 0, time.UTC),
+			expectedEnd:   time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC),
+		},
+	} {
+		t.Run(tc.period, func(t *testing.T) {
+			start := reconciler.BudgetPeriodStart(tc.period, now)
+			if !start.Equal(tc.expectedStart) {
+				t.Errorf("start: expected %s, got %s", tc.expectedStart, start)
+			}
+
+			end := reconciler.BudgetPeriodEnd(tc.period, start)
+			if !end.Equal(tc.expectedEnd) {
+				t.Errorf("end: expected %s, got %s", tc.expectedEnd, end)
+			}
+		})
+	}
+}
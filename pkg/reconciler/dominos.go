@@ -0,0 +1,67 @@
+package reconciler
+
+import (
+	"errors"
+	"time"
+
+	"github.com/go-logr/logr"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+// newDominosClient instantiates a Domino's client for a customer (see
+// dominos.Config), reporting metrics about the requests it makes, and
+// logging through `log`.
+func newDominosClient(
+	config dominos.Config,
+	customer *v1alpha1.PizzaCustomer,
+	log logr.Logger,
+) (*dominos.Client, error) {
+	return config.NewClient(customer,
+		dominos.WithMetrics(dominosMetrics),
+		dominos.WithLogger(log),
+	)
+}
+
+// RejectionRequeueDelay is how long to wait before reconciling again an
+// object whose reconciliation got rejected by Domino's.
+const RejectionRequeueDelay = 3 * time.Minute
+
+// ResultForDominosError determines how to follow up on a reconciliation that
+// failed with `err`: requests that got canceled (e.g., the controller is
+// shutting down) are left alone, and there's no point in retrying right away
+// what Domino's has rejected - it's tried again only later on (see
+// RejectionRequeueDelay). Anything else gets retried with backoff.
+func ResultForDominosError(log logr.Logger, err error) (ctrl.Result, error) {
+	switch {
+	case dominos.IsCanceled(err):
+		log.Info("canceled")
+		return ctrl.Result{}, nil
+	case IsDominosRejection(err):
+		log.Error(err, "rejected by dominos")
+		return ctrl.Result{RequeueAfter: RejectionRequeueDelay}, nil
+	}
+
+	return ctrl.Result{}, err
+}
+
+// IsDominosRejection tells whether an error is due to Domino's having
+// rejected a request (rather than it having failed to go through), in which
+// case retrying it right away won't help.
+func IsDominosRejection(err error) bool {
+	_, ok := AsDominosRejection(err)
+	return ok
+}
+
+// AsDominosRejection retrieves the details of Domino's having rejected a
+// request, if that's what the error is due to.
+func AsDominosRejection(err error) (*dominos.APIError, bool) {
+	var apiErr *dominos.APIError
+	if !errors.As(err, &apiErr) || dominos.IsRetryable(err) {
+		return nil, false
+	}
+
+	return apiErr, true
+}
@@ -0,0 +1,51 @@
+package reconciler_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/reconciler"
+)
+
+func TestResultForDominosError(t *testing.T) {
+	for _, tc := range []struct {
+		name         string
+		err          error
+		requeueAfter bool
+		returned     bool
+	}{
+		{
+			name: "canceled",
+			err:  fmt.Errorf("price order: %w", context.Canceled),
+		},
+		{
+			name:         "rejected",
+			err:          fmt.Errorf("price order: %w", &dominos.APIError{StatusCode: 200}),
+			requeueAfter: true,
+		},
+		{
+			name:     "failed",
+			err:      errors.New("status update: conflict"),
+			returned: true,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := reconciler.ResultForDominosError(log.NullLogger{}, tc.err)
+
+			if (err != nil) != tc.returned {
+				t.Errorf("expected the error to be returned: %v, got %v", tc.returned, err)
+			}
+
+			if (res.RequeueAfter == reconciler.RejectionRequeueDelay) != tc.requeueAfter {
+				t.Errorf("expected to be requeued after %s: %v, got %+v",
+					reconciler.RejectionRequeueDelay, tc.requeueAfter, res,
+				)
+			}
+		})
+	}
+}
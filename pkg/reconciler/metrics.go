@@ -0,0 +1,52 @@
+package reconciler
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+)
+
+var (
+	ordersPriced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pizza_orders_priced_total",
+		Help: "Number of times that orders got priced, per namespace.",
+	}, []string{"namespace"})
+
+	ordersPlaced = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pizza_orders_placed_total",
+		Help: "Number of orders placed, per namespace.",
+	}, []string{"namespace"})
+
+	ordersFailed = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pizza_orders_failed_total",
+		Help: "Number of times that orders couldn't be priced or placed, per namespace and reason.",
+	}, []string{"namespace", "reason"})
+
+	spend = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "pizza_spend_total",
+		Help: "Total of the orders placed, per namespace and currency.",
+	}, []string{"namespace", "currency"})
+
+	customerStores = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "pizza_customer_stores",
+		Help: "Number of stores known to be nearby each customer.",
+	}, []string{"namespace", "customer"})
+
+	// dominosMetrics are reported to by every client that the reconcilers
+	// create.
+	//
+	dominosMetrics = dominos.NewMetrics()
+)
+
+func init() {
+	metrics.Registry.MustRegister(
+		ordersPriced,
+		ordersPlaced,
+		ordersFailed,
+		spend,
+		customerStores,
+	)
+
+	metrics.Registry.MustRegister(dominosMetrics.Collectors()...)
+}
@@ -0,0 +1,125 @@
+package reconciler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/go-logr/logr"
+)
+
+type PizzaBudgetReconciler struct {
+	Log    logr.Logger
+	Client client.Client
+}
+
+func (r *PizzaBudgetReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
+	log := r.Log.WithValues("name", req.NamespacedName)
+
+	log.Info("start")
+	defer func() {
+		if err != nil {
+			log.Error(err, "finished")
+		} else {
+			log.Info("finished")
+		}
+	}()
+
+	budget, err := r.GetPizzaBudget(ctx, req.Name, req.Namespace)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			return
+		}
+
+		err = fmt.Errorf("get pizza budget: %w", err)
+		return
+	}
+
+	periodEnd, err := r.ReconcilePizzaBudget(ctx, budget)
+	if err != nil {
+		err = fmt.Errorf("reconcile pizza budget: %w", err)
+		return
+	}
+
+	// orders being placed get the budget reconciled again, but the start
+	// of a new period doesn't - come back for it.
+	//
+	return ctrl.Result{
+		RequeueAfter: time.Until(periodEnd) + time.Second,
+	}, nil
+}
+
+// ReconcilePizzaBudget brings the consumption of the budget up to date with
+// the orders placed within its current period, reporting when that period
+// ends.
+func (r *PizzaBudgetReconciler) ReconcilePizzaBudget(
+	ctx context.Context,
+	budget *v1alpha1.PizzaBudget,
+) (time.Time, error) {
+	start := BudgetPeriodStart(budget.Spec.Period, time.Now())
+	end := BudgetPeriodEnd(budget.Spec.Period, start)
+
+	amount, err := dominos.ParseMoney(budget.Spec.Amount)
+	if err != nil {
+		return end, fmt.Errorf("amount: %w", err)
+	}
+
+	orders := &v1alpha1.PizzaOrderList{}
+	if err := r.Client.List(ctx, orders, client.InNamespace(budget.Namespace)); err != nil {
+		return end, fmt.Errorf("list orders: %w", err)
+	}
+
+	consumed := BudgetConsumption(orders.Items, budget.Spec.Currency, start, "")
+
+	remaining := amount - consumed
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	cond := metav1.Condition{
+		Type:               "Exhausted",
+		Status:             metav1.ConditionFalse,
+		Reason:             "BudgetAvailable",
+		Message:            fmt.Sprintf("%s out of %s spent", consumed, amount),
+		ObservedGeneration: budget.Generation,
+	}
+	if remaining == 0 {
+		cond.Status = metav1.ConditionTrue
+		cond.Reason = "BudgetExhausted"
+	}
+
+	periodStart := metav1.NewTime(start)
+	budget.Status.PeriodStart = &periodStart
+	budget.Status.Consumed = consumed.String()
+	budget.Status.Remaining = remaining.String()
+	meta.SetStatusCondition(&budget.Status.Conditions, cond)
+
+	if err := r.Client.Status().Update(ctx, budget); err != nil {
+		return end, fmt.Errorf("status update: %w", err)
+	}
+
+	return end, nil
+}
+
+func (r *PizzaBudgetReconciler) GetPizzaBudget(
+	ctx context.Context,
+	name, namespace string,
+) (*v1alpha1.PizzaBudget, error) {
+	obj := &v1alpha1.PizzaBudget{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	return obj, nil
+}
@@ -7,8 +7,12 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -18,9 +22,21 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// DefaultMaxNearbyStores is the maximum number of stores close to a customer
+// that get PizzaStore objects created for, unless configured otherwise.
+const DefaultMaxNearbyStores = 3
+
 type PizzaCustomerReconciler struct {
-	Log    logr.Logger
-	Client client.Client
+	Log      logr.Logger
+	Client   client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+	Dominos  dominos.Config
+
+	// MaxNearbyStores is the maximum number of (open) stores close to a
+	// customer that get PizzaStore objects created for, the closest ones
+	// first - 0 for no limit.
+	MaxNearbyStores int
 }
 
 func (r *PizzaCustomerReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
@@ -38,6 +54,7 @@ func (r *PizzaCustomerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 	customer, err := r.GetPizzaCustomer(ctx, req.Name, req.Namespace)
 	if err != nil {
 		if errors.IsNotFound(err) {
+			customerStores.DeleteLabelValues(req.Namespace, req.Name)
 			return
 		}
 
@@ -47,8 +64,7 @@ func (r *PizzaCustomerReconciler) Reconcile(ctx context.Context, req ctrl.Reques
 
 	err = r.ReconcilePizzaCustomer(ctx, customer)
 	if err != nil {
-		err = fmt.Errorf("reconcile pizza customer: %w", err)
-		return
+		return ResultForDominosError(log, fmt.Errorf("reconcile pizza customer: %w", err))
 	}
 
 	return ctrl.Result{
@@ -60,73 +76,165 @@ func (r *PizzaCustomerReconciler) ReconcilePizzaCustomer(
 	ctx context.Context,
 	customer *v1alpha1.PizzaCustomer,
 ) error {
-	client, err := dominos.NewClient(dominos.CanadaURL, false)
+	client, err := newDominosClient(r.Dominos, customer, r.Log.WithName("dominos"))
 	if err != nil {
 		return fmt.Errorf("new client: %w", err)
 	}
 
-	stores, err := client.StoresNearby(ctx, dominos.Address{
-		StreetNumber: customer.Spec.StreetNumber,
-		StreetName:   customer.Spec.StreetName,
-		City:         customer.Spec.City,
-		State:        customer.Spec.State,
-		Zip:          customer.Spec.Zip,
-	}, dominos.ServiceDelivery)
+	located, err := client.LocateStores(ctx,
+		dominos.CustomerAddress(customer), dominos.ServiceDelivery,
+	)
 	if err != nil {
-		return fmt.Errorf("stores nearby: %w", err)
+		return fmt.Errorf("locate stores: %w", err)
+	}
+
+	// stores that are just closed for now (e.g., overnight) are still
+	// nearby the customer, so only the ones that the locator doesn't
+	// return at all anymore get released - and none if it returned
+	// nothing, as that says more about the lookup than about the stores.
+	//
+	stores, nearby := []*dominos.Store{}, []corev1.LocalObjectReference{}
+	for _, store := range located {
+		nearby = append(nearby, corev1.LocalObjectReference{Name: PizzaStoreName(store)})
+
+		if store.Open {
+			stores = append(stores, store)
+		}
 	}
 
-	if len(stores) >= 3 {
-		stores = stores[:3]
+	if len(located) != 0 {
+		if err := r.ReleaseStores(ctx, customer, nearby); err != nil {
+			return fmt.Errorf("release stores: %w", err)
+		}
 	}
 
-	refs := []*corev1.LocalObjectReference{}
+	ready := meta.FindStatusCondition(customer.Status.Conditions, "Ready")
+
+	if len(stores) == 0 {
+		customer.Status.ClosestStoreRef = corev1.LocalObjectReference{}
+		customer.Status.StoreRefs = nil
+		meta.SetStatusCondition(&customer.Status.Conditions, metav1.Condition{
+			Type:    "Ready",
+			Status:  metav1.ConditionFalse,
+			Reason:  "NoStoresFound",
+			Message: "no open stores delivering to the customer's address were found",
+		})
+
+		if err := r.Client.Status().Update(ctx, customer); err != nil {
+			return fmt.Errorf("status update: %w", err)
+		}
+
+		customerStores.WithLabelValues(customer.Namespace, customer.Name).Set(0)
+
+		if ready == nil || ready.Reason != "NoStoresFound" {
+			r.Recorder.Event(customer, corev1.EventTypeWarning, "NoStoresFound",
+				"no open stores delivering to the customer's address were found",
+			)
+		}
+
+		return nil
+	}
+
+	if r.MaxNearbyStores > 0 && len(stores) > r.MaxNearbyStores {
+		stores = stores[:r.MaxNearbyStores]
+	}
+
+	refs := []corev1.LocalObjectReference{}
 	for _, store := range stores {
-		products, err := client.StoreMenu(ctx, store.ID)
+		// the menu is retrieved in the language of the store rather
+		// than the one of the customer, so that customers sharing a
+		// store don't keep on rewriting it in theirs.
+		//
+		menu, err := client.StoreMenuIn(ctx, store.ID, store.Language)
 		if err != nil {
 			return fmt.Errorf("store menu '%s': %w", store.ID, err)
 		}
 
-		pizzaStore := r.AssemblePizzaStore(customer, store, products)
-		pizzaStoreRef, err := r.FindOrCreate(ctx, pizzaStore)
+		pizzaStore := r.AssemblePizzaStore(customer, store, menu)
+		pizzaStoreRef, err := r.CreateOrUpdate(ctx, customer, pizzaStore)
 		if err != nil {
-			return fmt.Errorf("find or create: %w", err)
+			return fmt.Errorf("create or update '%s': %w", pizzaStore.Name, err)
 		}
 
-		refs = append(refs, pizzaStoreRef)
+		refs = append(refs, *pizzaStoreRef)
 	}
 
-	customer.Status.ClosestStoreRef = *refs[0]
-	customer.Status.Conditions = []metav1.Condition{
-		{
-			Type:               "Ready",
-			Status:             metav1.ConditionTrue,
-			Reason:             "StoresFound",
-			LastTransitionTime: metav1.Now(),
-		},
-	}
+	discovered := !sameStoreRefs(customer.Status.StoreRefs, refs)
+
+	customer.Status.ClosestStoreRef = refs[0]
+	customer.Status.StoreRefs = refs
+	meta.SetStatusCondition(&customer.Status.Conditions, metav1.Condition{
+		Type:    "Ready",
+		Status:  metav1.ConditionTrue,
+		Reason:  "StoresFound",
+		Message: fmt.Sprintf("found %d store(s) nearby", len(refs)),
+	})
 
 	if err := r.Client.Status().Update(ctx, customer); err != nil {
 		return fmt.Errorf("status update: %w", err)
 	}
 
+	customerStores.WithLabelValues(customer.Namespace, customer.Name).Set(float64(len(refs)))
+
+	if discovered {
+		names := []string{}
+		for _, ref := range refs {
+			names = append(names, ref.Name)
+		}
+
+		r.Recorder.Eventf(customer, corev1.EventTypeNormal, "StoresDiscovered",
+			"found %d store(s) nearby: %s", len(refs), strings.Join(names, ", "),
+		)
+	}
+
 	return nil
 }
 
-func (r *PizzaCustomerReconciler) FindOrCreate(
+func sameStoreRefs(a, b []corev1.LocalObjectReference) bool {
+	if len(a) != len(b) {
+		return false
+	}
+
+	for idx := range a {
+		if a[idx].Name != b[idx].Name {
+			return false
+		}
+	}
+
+	return true
+}
+
+// CreateOrUpdate makes sure that a PizzaStore object exists with the spec and
+// status of `desired`, creating it if needed, or bringing it up to date
+// otherwise (the status only being updated when it changed).
+//
+// As a store might be nearby more than one customer, the customer is added
+// to the list of owners of the store, rather than being its controller. That
+// way, the store gets garbage collected only once all of the customers that
+// found it are gone.
+func (r *PizzaCustomerReconciler) CreateOrUpdate(
 	ctx context.Context,
-	obj controllerutil.Object,
+	customer *v1alpha1.PizzaCustomer,
+	desired *v1alpha1.PizzaStore,
 ) (*corev1.LocalObjectReference, error) {
-	if err := r.Client.Create(ctx, obj); err != nil {
-		if !errors.IsAlreadyExists(err) {
-			return nil, fmt.Errorf("create: %w", err)
-		}
+	obj := &v1alpha1.PizzaStore{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      desired.Name,
+			Namespace: desired.Namespace,
+		},
+	}
 
-		if err := r.Client.Get(ctx, client.ObjectKey{
-			Name:      obj.GetName(),
-			Namespace: obj.GetNamespace(),
-		}, obj); err != nil {
-			return nil, fmt.Errorf("get: %w", err)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, obj, func() error {
+		obj.Spec = desired.Spec
+		return controllerutil.SetOwnerReference(customer, obj, r.Scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("create or update: %w", err)
+	}
+
+	if !equality.Semantic.DeepEqual(obj.Status, desired.Status) {
+		obj.Status = desired.Status
+		if err := r.Client.Status().Update(ctx, obj); err != nil {
+			return nil, fmt.Errorf("status update: %w", err)
 		}
 	}
 
@@ -135,14 +243,66 @@ func (r *PizzaCustomerReconciler) FindOrCreate(
 	}, nil
 }
 
+// ReleaseStores removes the customer from the owners of the stores that are
+// no longer nearby it (i.e., not in `refs`, open or not), deleting the ones
+// that end up not being owned by any customer.
+func (r *PizzaCustomerReconciler) ReleaseStores(
+	ctx context.Context,
+	customer *v1alpha1.PizzaCustomer,
+	refs []corev1.LocalObjectReference,
+) error {
+	keep := map[string]bool{}
+	for _, ref := range refs {
+		keep[ref.Name] = true
+	}
+
+	stores := &v1alpha1.PizzaStoreList{}
+	if err := r.Client.List(ctx, stores, client.InNamespace(customer.Namespace)); err != nil {
+		return fmt.Errorf("list: %w", err)
+	}
+
+	for idx := range stores.Items {
+		store := &stores.Items[idx]
+		if keep[store.Name] {
+			continue
+		}
+
+		owners := []metav1.OwnerReference{}
+		for _, owner := range store.OwnerReferences {
+			if owner.UID != customer.UID {
+				owners = append(owners, owner)
+			}
+		}
+
+		if len(owners) == len(store.OwnerReferences) {
+			continue
+		}
+
+		if len(owners) == 0 {
+			if err := r.Client.Delete(ctx, store); err != nil && !errors.IsNotFound(err) {
+				return fmt.Errorf("delete '%s': %w", store.Name, err)
+			}
+
+			continue
+		}
+
+		store.OwnerReferences = owners
+		if err := r.Client.Update(ctx, store); err != nil {
+			return fmt.Errorf("update '%s': %w", store.Name, err)
+		}
+	}
+
+	return nil
+}
+
 func (r *PizzaCustomerReconciler) AssemblePizzaStore(
 	customer *v1alpha1.PizzaCustomer,
 	store *dominos.Store,
-	products []*dominos.Product,
+	menu *dominos.Menu,
 ) *v1alpha1.PizzaStore {
 
 	specProducts := []v1alpha1.PizzaStoreProduct{}
-	for _, product := range products {
+	for _, product := range menu.Preconfigured {
 		specProducts = append(specProducts, v1alpha1.PizzaStoreProduct{
 			Name:        product.Name,
 			ID:          product.ID,
@@ -151,20 +311,90 @@ func (r *PizzaCustomerReconciler) AssemblePizzaStore(
 		})
 	}
 
+	coupons := []v1alpha1.PizzaStoreCoupon{}
+	for _, coupon := range menu.Coupons {
+		coupons = append(coupons, v1alpha1.PizzaStoreCoupon{
+			Code:        coupon.Code,
+			Name:        coupon.Name,
+			Description: coupon.Description,
+			Price:       coupon.Price,
+		})
+	}
+
+	serviceMethods := []string{}
+	for _, service := range store.Services {
+		serviceMethods = append(serviceMethods, string(service))
+	}
+
+	paymentTypes := []string{}
+	for _, paymentType := range store.PaymentTypes {
+		paymentTypes = append(paymentTypes, string(paymentType))
+	}
+
 	return &v1alpha1.PizzaStore{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      "store-" + strings.ToLower(store.ID),
+			Name:      PizzaStoreName(store),
 			Namespace: customer.Namespace,
 		},
 		Spec: v1alpha1.PizzaStoreSpec{
-			Address:  store.Address,
-			ID:       store.ID,
-			Phone:    store.Phone,
-			Products: specProducts,
+			Address:        store.Address,
+			ID:             store.ID,
+			Phone:          store.Phone,
+			ServiceMethods: serviceMethods,
+			PaymentTypes:   paymentTypes,
+			Products:       specProducts,
+			Coupons:        coupons,
+		},
+		Status: v1alpha1.PizzaStoreStatus{
+			Menu: AssemblePizzaStoreMenu(menu),
 		},
 	}
 }
 
+// PizzaStoreName names the PizzaStore object of a Domino's store.
+func PizzaStoreName(store *dominos.Store) string {
+	return "store-" + strings.ToLower(store.ID)
+}
+
+// AssemblePizzaStoreMenu trims the menu of a store down to what's kept in the
+// status of its PizzaStore object: codes, names, and prices.
+func AssemblePizzaStoreMenu(menu *dominos.Menu) *v1alpha1.PizzaStoreMenu {
+	res := &v1alpha1.PizzaStoreMenu{}
+
+	for _, product := range menu.Products {
+		res.Products = append(res.Products, v1alpha1.PizzaStoreMenuProduct{
+			ID:       product.Code,
+			Name:     product.Name,
+			Variants: product.Variants,
+		})
+	}
+
+	for _, variant := range menu.Variants {
+		res.Variants = append(res.Variants, v1alpha1.PizzaStoreMenuVariant{
+			ID:        variant.Code,
+			Name:      variant.Name,
+			ProductID: variant.ProductCode,
+			Price:     variant.Price,
+		})
+	}
+
+	for _, topping := range menu.Toppings {
+		res.Toppings = append(res.Toppings, v1alpha1.PizzaStoreMenuOption{
+			Code: topping.Code,
+			Name: topping.Name,
+		})
+	}
+
+	for _, side := range menu.Sides {
+		res.Sides = append(res.Sides, v1alpha1.PizzaStoreMenuOption{
+			Code: side.Code,
+			Name: side.Name,
+		})
+	}
+
+	return res
+}
+
 func (r *PizzaCustomerReconciler) GetPizzaCustomer(
 	ctx context.Context,
 	name, namespace string,
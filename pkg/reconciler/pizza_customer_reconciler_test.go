@@ -0,0 +1,118 @@
+package reconciler_test
+
+import (
+	"context"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/dominos/dominostest"
+	"github.com/cirocosta/pizza-controller/pkg/reconciler"
+)
+
+func TestPizzaCustomerReconcilerStoreMenu(t *testing.T) {
+	srv := dominostest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddStore(dominostest.Store{
+		ID:       "10391",
+		Carryout: true,
+		Delivery: true,
+		TaxRate:  0.13,
+		Products: []dominostest.Product{
+			{
+				Code:     "10SCREEN",
+				Name:     "Small Hand Tossed",
+				Price:    9.03,
+				Type:     "Pizza",
+				Toppings: []string{"X", "C", "P"},
+			},
+		},
+	})
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	c := fake.NewFakeClientWithScheme(scheme, &v1alpha1.PizzaCustomer{
+		ObjectMeta: metav1.ObjectMeta{Name: "barack", Namespace: namespace},
+		Spec: v1alpha1.PizzaCustomerSpec{
+			FirstName:    "barack",
+			LastName:     "obama",
+			StreetNumber: "90",
+			StreetName:   "Bremner Blvd",
+			City:         "Toronto",
+			State:        "ON",
+			Zip:          "M5J 0A9",
+		},
+	})
+
+	r := &reconciler.PizzaCustomerReconciler{
+		Log:      log.NullLogger{},
+		Client:   c,
+		Scheme:   scheme,
+		Recorder: record.NewFakeRecorder(100),
+		Dominos: dominos.Config{
+			URL:         srv.URL,
+			RetryPolicy: dominos.NoRetries,
+		},
+	}
+
+	reconcile := func() *v1alpha1.PizzaStore {
+		t.Helper()
+
+		if _, err := r.Reconcile(context.Background(), ctrl.Request{
+			NamespacedName: types.NamespacedName{Name: "barack", Namespace: namespace},
+		}); err != nil {
+			t.Fatalf("reconcile: %v", err)
+		}
+
+		store := &v1alpha1.PizzaStore{}
+		if err := c.Get(context.Background(), types.NamespacedName{
+			Name: "store-10391", Namespace: namespace,
+		}, store); err != nil {
+			t.Fatalf("get store: %v", err)
+		}
+
+		return store
+	}
+
+	store := reconcile()
+
+	menu := store.Status.Menu
+	if menu == nil {
+		t.Fatalf("expected the menu to be in the status")
+	}
+
+	if len(menu.Products) != 1 || menu.Products[0].ID != "10SCREEN" {
+		t.Fatalf("expected the 10SCREEN product, got %+v", menu.Products)
+	}
+
+	if len(menu.Variants) != 1 {
+		t.Fatalf("expected 1 variant, got %+v", menu.Variants)
+	}
+
+	if variant := menu.Variants[0]; variant.ID != "10SCREEN" || variant.Price != "9.03" {
+		t.Errorf("unexpected variant %+v", variant)
+	}
+
+	if again := reconcile(); again.ResourceVersion != store.ResourceVersion {
+		t.Errorf("expected the store not to be updated when nothing changed, "+
+			"but its resource version went from '%s' to '%s'",
+			store.ResourceVersion, again.ResourceVersion,
+		)
+	}
+}
@@ -2,14 +2,19 @@ package reconciler
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	"fmt"
-	"strconv"
 	"strings"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -18,9 +23,51 @@ import (
 	"github.com/go-logr/logr"
 )
 
+// RetryPlacementAnnotation, when set on an order whose placement couldn't be
+// confirmed, has the order tracker checked again for it. Only when set to
+// RetryPlacementNotPlaced (i.e., it's been verified with the store that the
+// order didn't go through) is the order placed again if the tracker doesn't
+// find it either.
+//
+// When set on an order whose placement Domino's rejected (see
+// IsPlacementRejected), it has the order placed again as is.
+const RetryPlacementAnnotation = "ops.tips/retry-placement"
+
+// RetryPlacementNotPlaced is the value of RetryPlacementAnnotation that
+// confirms that an order whose placement couldn't be confirmed didn't go
+// through.
+const RetryPlacementNotPlaced = "not-placed"
+
+const (
+	// TrackingInterval is how often placed orders are checked on with
+	// Domino's order tracker, until complete.
+	TrackingInterval = time.Minute
+
+	// TrackingTimeout is how long after being placed an order stops being
+	// tracked if the tracker doesn't know about it.
+	TrackingTimeout = 6 * time.Hour
+)
+
+// orderStageConditions maps the stages that the tracker reports orders at
+// to the conditions that tell they've been reached.
+var orderStageConditions = map[dominos.OrderStage]string{
+	dominos.OrderStageMaking:         "Making",
+	dominos.OrderStageOven:           "Oven",
+	dominos.OrderStageQualityCheck:   "QualityCheck",
+	dominos.OrderStageOutForDelivery: "OutForDelivery",
+	dominos.OrderStageComplete:       "Complete",
+}
+
 type PizzaOrderReconciler struct {
-	Log    logr.Logger
-	Client client.Client
+	Log      logr.Logger
+	Client   client.Client
+	Recorder record.EventRecorder
+	Dominos  dominos.Config
+
+	// APIReader reads straight from the API server rather than from the
+	// cache that Client reads from, for what must be up to date before an
+	// order gets placed (e.g., how much of a budget other orders spent).
+	APIReader client.Reader
 }
 
 func (r *PizzaOrderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (res ctrl.Result, err error) {
@@ -47,8 +94,18 @@ func (r *PizzaOrderReconciler) Reconcile(ctx context.Context, req ctrl.Request)
 
 	err = r.ReconcilePizzaOrder(ctx, order)
 	if err != nil {
-		err = fmt.Errorf("reconcile pizza order: %w", err)
-		return
+		return ResultForDominosError(log, fmt.Errorf("reconcile pizza order: %w", err))
+	}
+
+	switch {
+	case IsOrderComplete(order):
+		return ctrl.Result{}, nil
+	case r.IsOrderAlreadyPlaced(order):
+		return ctrl.Result{
+			RequeueAfter: TrackingInterval,
+		}, nil
+	case IsOrderRejected(order):
+		return ctrl.Result{}, nil
 	}
 
 	return ctrl.Result{
@@ -61,110 +118,711 @@ func (r *PizzaOrderReconciler) ReconcilePizzaOrder(
 	order *v1alpha1.PizzaOrder,
 ) error {
 	if r.IsOrderAlreadyPlaced(order) {
-		return nil
+		return r.TrackOrder(ctx, order)
 	}
 
-	client, err := dominos.NewClient(dominos.CanadaURL, true)
+	customer, err := r.GetPizzaCustomer(ctx,
+		order.Spec.CustomerRef.Name, order.Namespace,
+	)
+	if err != nil {
+		return fmt.Errorf("get pizza customer '%s': %w",
+			order.Spec.CustomerRef.Name, err,
+		)
+	}
+
+	store, err := r.GetPizzaStore(ctx,
+		order.Spec.StoreRef.Name, order.Namespace,
+	)
+	if err != nil {
+		return fmt.Errorf("get pizza store '%s': %w",
+			order.Spec.StoreRef.Name, err,
+		)
+	}
+
+	client, err := newDominosClient(r.Dominos, customer, r.Log.WithName("dominos"))
 	if err != nil {
 		return fmt.Errorf("new client: %w", err)
 	}
 
-	dominosOrder, err := r.AssembleDominosOrder(ctx, order)
+	dominosOrder, err := r.AssembleDominosOrder(ctx, order, customer, store)
 	if err != nil {
 		return fmt.Errorf("assemble dominos order: %w", err)
 	}
 
-	if !r.IsOrderAlreadyPriced(order) {
+	total, err := OrderTotal(order)
+	if err != nil {
+		return fmt.Errorf("order total: %w", err)
+	}
+
+	dominosOrder.Amount = total
+
+	// an order that a previous attempt might have placed is left alone
+	// until asked to be placed again, and even then, the tracker is
+	// checked for it before anything else (e.g., pricing it again) can
+	// take it for an order that hasn't been placed.
+	//
+	if r.IsPlacementUnconfirmed(order) {
+		placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+		if !IsPlacementRetryRequested(order) {
+			return r.UnconfirmPlacement(ctx, order, placed)
+		}
+
+		resolved, err := r.ResolvePlacement(ctx, client, order, dominosOrder)
+		if err != nil || !resolved {
+			return err
+		}
+	}
+
+	// an order that Domino's refused to place (e.g., as the store was
+	// closed) isn't placed again on its own - say, hours later, once the
+	// store reopens - but only once it changes, or is asked to be.
+	//
+	if IsPlacementRejected(order) {
+		if !IsPlacementRetryRequested(order) {
+			return nil
+		}
+
+		if err := r.RemoveRetryPlacementAnnotation(ctx, order); err != nil {
+			return fmt.Errorf("remove retry placement annotation: %w", err)
+		}
+	}
+
+	fingerprint, err := PricingFingerprint(order, customer)
+	if err != nil {
+		return fmt.Errorf("pricing fingerprint: %w", err)
+	}
+
+	if !r.IsOrderAlreadyPriced(order, fingerprint) {
+		if err := ValidateServiceMethod(store, dominosOrder.Service); err != nil {
+			return r.RejectOrder(ctx, order, "OrderPriced", "ServiceMethodNotAllowed", err.Error())
+		}
+
+		if err := ValidatePaymentType(store, dominosOrder.PaymentType); err != nil {
+			return r.RejectOrder(ctx, order, "OrderPriced", "PaymentTypeNotAllowed", err.Error())
+		}
+
+		menu, err := client.StoreMenu(ctx, dominosOrder.StoreID)
+		if err != nil {
+			return fmt.Errorf("store menu: %w", err)
+		}
+
+		if err := menu.ValidateProducts(dominosOrder.Products); err != nil {
+			return r.RejectOrder(ctx, order, "OrderPriced", "InvalidProducts", err.Error())
+		}
+
+		if err := menu.ValidateCoupons(dominosOrder.Coupons); err != nil {
+			return r.RejectOrder(ctx, order, "OrderPriced", "InvalidCoupons", err.Error())
+		}
+
 		price, err := client.PriceOrder(ctx, *dominosOrder)
 		if err != nil {
+			if apiErr, ok := AsDominosRejection(err); ok {
+				return r.RejectOrder(ctx, order, "OrderPriced", apiErr.Reason(), apiErr.Message())
+			}
+
 			return fmt.Errorf("price order: %w", err)
 		}
 
-		order.Status.Price = price
-		order.Status.Conditions = append(order.Status.Conditions, metav1.Condition{
+		// an order placed with a coupon that doesn't apply would cost
+		// more than the customer expects it to.
+		//
+		for _, coupon := range price.Coupons {
+			if !coupon.Applied() {
+				return r.RejectOrder(ctx, order, "OrderPriced", coupon.Reason(), coupon.Message())
+			}
+		}
+
+		previous := order.Status.Price
+
+		order.Status.Price = dominos.AssemblePriceStatus(price)
+		order.Status.PricedFingerprint = fingerprint
+		order.Status.ObservedGeneration = order.Generation
+		meta.SetStatusCondition(&order.Status.Conditions, metav1.Condition{
 			Type:               "OrderPriced",
 			Status:             metav1.ConditionTrue,
 			Reason:             "OrderPriced",
-			LastTransitionTime: metav1.Now(),
+			Message:            "priced at " + FormatPrice(order.Status.Price),
+			ObservedGeneration: order.Generation,
 		})
 		if err := r.Client.Status().Update(ctx, order); err != nil {
 			return fmt.Errorf("price status update: %w", err)
 		}
 
+		ordersPriced.WithLabelValues(order.Namespace).Inc()
+
+		if previous != nil && previous.Total != order.Status.Price.Total {
+			r.Recorder.Eventf(order, corev1.EventTypeNormal, "PriceChanged",
+				"price changed from %s to %s", FormatPrice(previous), FormatPrice(order.Status.Price),
+			)
+		} else {
+			r.Recorder.Event(order, corev1.EventTypeNormal, "Priced",
+				"priced at "+FormatPrice(order.Status.Price),
+			)
+		}
+
 		return nil
 	}
 
-	price, err := strconv.ParseFloat(order.Status.Price, 64)
+	withinBudget, err := r.CheckBudget(ctx, order)
 	if err != nil {
-		return fmt.Errorf("parse float '%s': %w",
-			order.Status.Price, err,
+		return fmt.Errorf("check budget: %w", err)
+	}
+
+	if !order.Spec.YeahSurePlaceTheOrder || !withinBudget {
+		return nil
+	}
+
+	if !IsPriceAcknowledged(order) {
+		return r.RejectOrder(ctx, order, "OrderPlaced", "PriceNotAcknowledged",
+			fmt.Sprintf("set spec.acknowledgedPrice to %s to place the order", order.Status.Price.Total),
 		)
 	}
 
-	dominosOrder.Amount = price
+	return r.PlaceOrder(ctx, client, order, dominosOrder)
+}
 
-	if order.Spec.YeahSurePlaceTheOrder {
-		orderID, err := client.PlaceOrder(ctx, *dominosOrder)
-		if err != nil {
-			return fmt.Errorf("place order: %w", err)
+// PricingFingerprint hashes the contents of the spec of an order that are
+// taken into account when pricing it, along with the address of the customer
+// (which, e.g., delivery fees and taxes depend on).
+func PricingFingerprint(order *v1alpha1.PizzaOrder, customer *v1alpha1.PizzaCustomer) (string, error) {
+	b, err := json.Marshal(struct {
+		StoreRef      string
+		CustomerRef   string
+		Address       dominos.Address
+		Products      []v1alpha1.PizzaOrderProduct
+		Coupons       []string `json:",omitempty"`
+		ServiceMethod string
+		PaymentType   string
+	}{
+		StoreRef:      order.Spec.StoreRef.Name,
+		CustomerRef:   order.Spec.CustomerRef.Name,
+		Address:       dominos.CustomerAddress(customer),
+		Products:      order.Spec.Products,
+		Coupons:       order.Spec.Coupons,
+		ServiceMethod: order.Spec.ServiceMethod,
+		PaymentType:   order.Spec.PaymentType,
+	})
+	if err != nil {
+		return "", fmt.Errorf("marshal: %w", err)
+	}
+
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])[:16], nil
+}
+
+// IsPriceAcknowledged tells whether the price that the customer agreed to
+// pay matches the one that the order got priced at.
+func IsPriceAcknowledged(order *v1alpha1.PizzaOrder) bool {
+	if order.Spec.AcknowledgedPrice == "" || order.Status.Price == nil {
+		return false
+	}
+
+	acknowledged, err := dominos.ParseMoney(order.Spec.AcknowledgedPrice)
+	if err != nil {
+		return false
+	}
+
+	total, err := dominos.ParseMoney(order.Status.Price.Total)
+	if err != nil {
+		return false
+	}
+
+	return acknowledged == total
+}
+
+// OrderTotal retrieves the total that an order got priced at (zero if it
+// hasn't been priced yet).
+func OrderTotal(order *v1alpha1.PizzaOrder) (dominos.Money, error) {
+	if order.Status.Price == nil {
+		return 0, nil
+	}
+
+	total, err := dominos.ParseMoney(order.Status.Price.Total)
+	if err != nil {
+		return 0, fmt.Errorf("parse total: %w", err)
+	}
+
+	return total, nil
+}
+
+// FormatPrice describes the total of a price, e.g., "15.81 CAD".
+func FormatPrice(price *v1alpha1.PizzaOrderPrice) string {
+	if price.Currency == "" {
+		return price.Total
+	}
+
+	return price.Total + " " + price.Currency
+}
+
+// PlaceOrder places the order with Domino's, making sure that it doesn't get
+// placed more than once.
+//
+// Before reaching out to Domino's, the `OrderPlaced` condition is set to
+// `Unknown` (with the `Placing` reason) and persisted, so that if the result
+// of placing the order doesn't get recorded (e.g., the controller crashed,
+// or the response got lost), the order is not blindly placed again: it gets
+// marked as `PlacementUnconfirmed` instead, to be resolved by a human (see
+// RetryPlacementAnnotation).
+func (r *PizzaOrderReconciler) PlaceOrder(
+	ctx context.Context,
+	client *dominos.Client,
+	order *v1alpha1.PizzaOrder,
+	dominosOrder *dominos.Order,
+) error {
+	placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+	if placed != nil && placed.Status == metav1.ConditionUnknown {
+		return r.UnconfirmPlacement(ctx, order, placed)
+	}
+
+	// an order that Domino's client would refuse to send is rejected
+	// before being marked as being placed, as there's nothing to
+	// confirm about it.
+	//
+	if err := dominosOrder.Validate(); err != nil {
+		return r.RejectOrder(ctx, order, "OrderPlaced", "InvalidOrder", err.Error())
+	}
+
+	order.Status.OrderKey = OrderKey(order)
+	order.Status.ObservedGeneration = order.Generation
+	meta.SetStatusCondition(&order.Status.Conditions, metav1.Condition{
+		Type:               "OrderPlaced",
+		Status:             metav1.ConditionUnknown,
+		Reason:             "Placing",
+		Message:            "placing order with key " + order.Status.OrderKey,
+		ObservedGeneration: order.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return fmt.Errorf("placing status update: %w", err)
+	}
+
+	dominosOrder.Key = order.Status.OrderKey
+	placedOrder, placeErr := client.PlaceOrder(ctx, *dominosOrder)
+
+	// the outcome must be recorded even if the reconciliation got canceled
+	// in the meantime, otherwise it'd be left as unconfirmed.
+	//
+	recordCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	var cond metav1.Condition
+	switch apiErr, rejected := AsDominosRejection(placeErr); {
+	case placeErr == nil:
+		cond = metav1.Condition{
+			Type:   "OrderPlaced",
+			Status: metav1.ConditionTrue,
+			Reason: "OrderPlaced",
 		}
+	case rejected:
+		cond = metav1.Condition{
+			Type:    "OrderPlaced",
+			Status:  metav1.ConditionFalse,
+			Reason:  apiErr.Reason(),
+			Message: apiErr.Message(),
+		}
+	case dominos.IsNotSent(placeErr):
+		cond = metav1.Condition{
+			Type:    "OrderPlaced",
+			Status:  metav1.ConditionFalse,
+			Reason:  "NotSent",
+			Message: placeErr.Error(),
+		}
+	default:
+		cond = metav1.Condition{
+			Type:    "OrderPlaced",
+			Status:  metav1.ConditionUnknown,
+			Reason:  "PlacementUnconfirmed",
+			Message: placeErr.Error(),
+		}
+	}
 
-		order.Status.OrderID = orderID
-		order.Status.Conditions = append(order.Status.Conditions, metav1.Condition{
-			Type:               "OrderPlaced",
-			Status:             metav1.ConditionTrue,
-			Reason:             "OrderPlaced",
-			LastTransitionTime: metav1.Now(),
-		})
-		if err := r.Client.Status().Update(ctx, order); err != nil {
-			return fmt.Errorf("price status update: %w", err)
+	cond.ObservedGeneration = order.Generation
+
+	if err := r.UpdateStatus(recordCtx, order, func(order *v1alpha1.PizzaOrder) {
+		if placeErr == nil {
+			order.Status.OrderID = placedOrder.ID
+			order.Status.EstimatedWaitMinutes = placedOrder.EstimatedWaitMinutes
+			order.Status.Payment = AssemblePaymentStatus(dominosOrder)
 		}
+
+		meta.SetStatusCondition(&order.Status.Conditions, cond)
+	}); err != nil {
+		return fmt.Errorf("place status update: %w", err)
+	}
+
+	switch cond.Status {
+	case metav1.ConditionTrue:
+		ordersPlaced.WithLabelValues(order.Namespace).Inc()
+		spend.WithLabelValues(order.Namespace, order.Status.Price.Currency).
+			Add(dominosOrder.Amount.Float64())
+
+		r.Recorder.Eventf(order, corev1.EventTypeNormal, "Placed",
+			"placed as order %s (estimated wait: %s minutes)",
+			placedOrder.ID, placedOrder.EstimatedWaitMinutes,
+		)
+	case metav1.ConditionFalse:
+		ordersFailed.WithLabelValues(order.Namespace, cond.Reason).Inc()
+
+		r.Recorder.Eventf(order, corev1.EventTypeWarning, "PlacementFailed",
+			"%s: %s", cond.Reason, cond.Message,
+		)
+	default:
+		r.Recorder.Event(order, corev1.EventTypeWarning, "PlacementUnconfirmed", cond.Message)
+	}
+
+	if cond.Reason == "NotSent" {
+		return fmt.Errorf("place order: %w", placeErr)
 	}
 
 	return nil
 }
 
-func (r *PizzaOrderReconciler) AssembleDominosOrder(
+// TrackOrder brings the stage of a placed order up to date with Domino's
+// order tracker, setting a condition for each of the stages that it reached
+// (see orderStageConditions).
+func (r *PizzaOrderReconciler) TrackOrder(
 	ctx context.Context,
 	order *v1alpha1.PizzaOrder,
-) (*dominos.Order, error) {
+) error {
+	if IsOrderComplete(order) {
+		return nil
+	}
+
 	customer, err := r.GetPizzaCustomer(ctx,
 		order.Spec.CustomerRef.Name, order.Namespace,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get pizza customer '%s': %w",
+		return fmt.Errorf("get pizza customer '%s': %w",
 			order.Spec.CustomerRef.Name, err,
 		)
 	}
 
-	store, err := r.GetPizzaStore(ctx,
-		order.Spec.StoreRef.Name, order.Namespace,
+	client, err := newDominosClient(r.Dominos, customer, r.Log.WithName("dominos"))
+	if err != nil {
+		return fmt.Errorf("new client: %w", err)
+	}
+
+	tracked, found, err := client.TrackOrder(ctx, customer.Spec.Phone, order.Status.OrderID)
+	if err != nil {
+		return fmt.Errorf("track order: %w", err)
+	}
+
+	if !found {
+		// the tracker only knows about recent orders: if it didn't get
+		// to know about this one by now, it never will.
+		//
+		placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+		if placed == nil || time.Since(placed.LastTransitionTime.Time) < TrackingTimeout {
+			return nil
+		}
+
+		cond := metav1.Condition{
+			Type:               "Complete",
+			Status:             metav1.ConditionUnknown,
+			Reason:             "TrackingTimedOut",
+			Message:            fmt.Sprintf("order not found by the tracker %s after being placed", TrackingTimeout),
+			ObservedGeneration: order.Generation,
+		}
+
+		meta.SetStatusCondition(&order.Status.Conditions, cond)
+		if err := r.Client.Status().Update(ctx, order); err != nil {
+			return fmt.Errorf("tracking status update: %w", err)
+		}
+
+		r.Recorder.Event(order, corev1.EventTypeWarning, cond.Reason, cond.Message)
+		return nil
+	}
+
+	if _, known := orderStageConditions[tracked.Stage]; !known {
+		r.Log.Info("unknown order stage", "order", order.Name, "stage", tracked.Stage)
+		return nil
+	}
+
+	reached := []metav1.Condition{}
+	for _, stage := range dominos.OrderStages {
+		condType := orderStageConditions[stage]
+
+		skip := stage == dominos.OrderStageOutForDelivery &&
+			dominos.OrderServiceMethod(order) != dominos.ServiceDelivery
+		if !skip && !meta.IsStatusConditionTrue(order.Status.Conditions, condType) {
+			message := "reached"
+			if at, found := tracked.StageTimes[stage]; found {
+				message = "reached at " + at + " (store time)"
+			}
+
+			cond := metav1.Condition{
+				Type:               condType,
+				Status:             metav1.ConditionTrue,
+				Reason:             condType,
+				Message:            message,
+				ObservedGeneration: order.Generation,
+			}
+
+			meta.SetStatusCondition(&order.Status.Conditions, cond)
+			order.Status.Stage = condType
+			reached = append(reached, cond)
+		}
+
+		if stage == tracked.Stage {
+			break
+		}
+	}
+
+	if len(reached) == 0 {
+		return nil
+	}
+
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return fmt.Errorf("tracking status update: %w", err)
+	}
+
+	for _, cond := range reached {
+		r.Recorder.Event(order, corev1.EventTypeNormal, cond.Reason, cond.Message)
+	}
+
+	return nil
+}
+
+// UnconfirmPlacement marks an order that a previous attempt at placing might
+// (or might not) have placed as such.
+func (r *PizzaOrderReconciler) UnconfirmPlacement(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+	placed *metav1.Condition,
+) error {
+	if placed.Reason == "PlacementUnconfirmed" {
+		return nil
+	}
+
+	cond := metav1.Condition{
+		Type:   "OrderPlaced",
+		Status: metav1.ConditionUnknown,
+		Reason: "PlacementUnconfirmed",
+		Message: fmt.Sprintf("the outcome of placing the order with key %s was not recorded",
+			order.Status.OrderKey,
+		),
+	}
+
+	meta.SetStatusCondition(&order.Status.Conditions, cond)
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return fmt.Errorf("unconfirmed status update: %w", err)
+	}
+
+	r.Recorder.Event(order, corev1.EventTypeWarning, cond.Reason, cond.Message)
+	return nil
+}
+
+// ResolvePlacement checks with the order tracker whether an order whose
+// placement couldn't be confirmed went through after all (e.g., with only the
+// response to placing it getting lost), reporting whether it can be told
+// that it either did (recording it as placed), or didn't (forgetting about
+// the previous attempt, so that the order can be placed again).
+//
+// As the tracker not finding the order doesn't prove that it wasn't placed,
+// the latter takes RetryPlacementAnnotation to be set to
+// RetryPlacementNotPlaced - otherwise, the order stays unconfirmed.
+func (r *PizzaOrderReconciler) ResolvePlacement(
+	ctx context.Context,
+	client *dominos.Client,
+	order *v1alpha1.PizzaOrder,
+	dominosOrder *dominos.Order,
+) (bool, error) {
+	tracked, found, err := client.TrackOrderByKey(ctx,
+		dominosOrder.PersonalInformation.Phone, OrderKey(order),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get pizza store '%s': %w",
-			order.Spec.StoreRef.Name, err,
-		)
+		return false, fmt.Errorf("track order by key: %w", err)
 	}
 
-	cc := &dominos.CreditCard{}
-	if order.Spec.YeahSurePlaceTheOrder {
-		cc, err = r.GetCreditCardInfo(ctx,
-			customer.Spec.CreditCardSecretRef.Name,
-			order.Namespace,
-		)
-		if err != nil {
-			return nil, fmt.Errorf("get credit card info: %w", err)
+	notPlaced := order.Annotations[RetryPlacementAnnotation] == RetryPlacementNotPlaced
+
+	if err := r.RemoveRetryPlacementAnnotation(ctx, order); err != nil {
+		return false, fmt.Errorf("remove retry placement annotation: %w", err)
+	}
+
+	switch {
+	case found:
+		return false, r.ConfirmPlacement(ctx, order, tracked, dominosOrder)
+	case !notPlaced:
+		cond := metav1.Condition{
+			Type:   "OrderPlaced",
+			Status: metav1.ConditionUnknown,
+			Reason: "PlacementUnconfirmed",
+			Message: fmt.Sprintf("the order with key %s was not found by the order tracker, "+
+				"which doesn't rule out that it got placed: once verified with the store "+
+				"that it wasn't, set the %s annotation to '%s' to place it again",
+				order.Status.OrderKey, RetryPlacementAnnotation, RetryPlacementNotPlaced,
+			),
+			ObservedGeneration: order.Generation,
+		}
+
+		meta.SetStatusCondition(&order.Status.Conditions, cond)
+		if err := r.Client.Status().Update(ctx, order); err != nil {
+			return false, fmt.Errorf("unconfirmed status update: %w", err)
+		}
+
+		r.Recorder.Event(order, corev1.EventTypeWarning, cond.Reason, cond.Message)
+		return false, nil
+	}
+
+	meta.RemoveStatusCondition(&order.Status.Conditions, "OrderPlaced")
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return false, fmt.Errorf("not placed status update: %w", err)
+	}
+
+	r.Recorder.Eventf(order, corev1.EventTypeNormal, "NotPlaced",
+		"order with key %s confirmed as not placed, and not found by the order tracker either",
+		order.Status.OrderKey,
+	)
+
+	return true, nil
+}
+
+// ConfirmPlacement records an order whose placement couldn't be confirmed,
+// but that the tracker found, as placed.
+func (r *PizzaOrderReconciler) ConfirmPlacement(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+	tracked *dominos.TrackedOrder,
+	dominosOrder *dominos.Order,
+) error {
+	cond := metav1.Condition{
+		Type:               "OrderPlaced",
+		Status:             metav1.ConditionTrue,
+		Reason:             "OrderPlaced",
+		Message:            "found by the order tracker as placed by a previous attempt",
+		ObservedGeneration: order.Generation,
+	}
+
+	order.Status.OrderID = tracked.OrderID
+	order.Status.Payment = AssemblePaymentStatus(dominosOrder)
+	meta.SetStatusCondition(&order.Status.Conditions, cond)
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return fmt.Errorf("confirmed status update: %w", err)
+	}
+
+	ordersPlaced.WithLabelValues(order.Namespace).Inc()
+	spend.WithLabelValues(order.Namespace, order.Status.Price.Currency).
+		Add(dominosOrder.Amount.Float64())
+
+	r.Recorder.Eventf(order, corev1.EventTypeNormal, "Placed",
+		"found by the order tracker as order %s, placed by a previous attempt", tracked.OrderID,
+	)
+
+	return nil
+}
+
+// RemoveRetryPlacementAnnotation removes the annotation that asks for an
+// order to be placed again, so that it's only acted on once.
+func (r *PizzaOrderReconciler) RemoveRetryPlacementAnnotation(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+) error {
+	delete(order.Annotations, RetryPlacementAnnotation)
+	if err := r.Client.Update(ctx, order); err != nil {
+		return fmt.Errorf("update: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateStatus updates the status of an order with `mutate`, retrying on
+// conflicts with the latest version of the order.
+func (r *PizzaOrderReconciler) UpdateStatus(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+	mutate func(*v1alpha1.PizzaOrder),
+) error {
+	first := true
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if !first {
+			if err := r.Client.Get(ctx, client.ObjectKey{
+				Name:      order.Name,
+				Namespace: order.Namespace,
+			}, order); err != nil {
+				return fmt.Errorf("get: %w", err)
+			}
 		}
+
+		first = false
+		mutate(order)
+
+		return r.Client.Status().Update(ctx, order)
+	})
+}
+
+// OrderKey derives the key that an order is placed with from its UID, so
+// that it's the same across attempts at placing it.
+func OrderKey(order *v1alpha1.PizzaOrder) string {
+	sum := sha256.Sum256([]byte(order.UID))
+	return hex.EncodeToString(sum[:])[:20]
+}
+
+// RejectOrder records on the order why it couldn't be priced or placed
+// (`conditionType` being either "OrderPriced" or "OrderPlaced"), so that
+// users can tell what went wrong without going through the controller logs.
+//
+// It only makes sense for failures that trying again won't fix without a
+// change to the order or to the store, so the order isn't requeued (see
+// IsOrderRejected): it's only reconciled again once it changes.
+func (r *PizzaOrderReconciler) RejectOrder(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+	conditionType, reason, message string,
+) error {
+	r.Log.Info("order rejected",
+		"name", order.Name, "namespace", order.Namespace,
+		"condition", conditionType, "reason", reason, "message", message,
+	)
+
+	// only the first time that an order gets rejected for a reason is
+	// worth an event - not every time it's reconciled after that.
+	//
+	existing := meta.FindStatusCondition(order.Status.Conditions, conditionType)
+	notify := existing == nil || existing.Status != metav1.ConditionFalse ||
+		existing.Reason != reason || existing.Message != message
+
+	order.Status.ObservedGeneration = order.Generation
+	meta.SetStatusCondition(&order.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             metav1.ConditionFalse,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: order.Generation,
+	})
+	if err := r.Client.Status().Update(ctx, order); err != nil {
+		return fmt.Errorf("rejection status update: %w", err)
+	}
+
+	if notify {
+		ordersFailed.WithLabelValues(order.Namespace, reason).Inc()
+		r.Recorder.Event(order, corev1.EventTypeWarning, reason, message)
 	}
 
+	return nil
+}
+
+func (r *PizzaOrderReconciler) AssembleDominosOrder(
+	ctx context.Context,
+	order *v1alpha1.PizzaOrder,
+	customer *v1alpha1.PizzaCustomer,
+	store *v1alpha1.PizzaStore,
+) (*dominos.Order, error) {
 	products := []dominos.Product{}
 	for _, product := range order.Spec.Products {
+		toppings, err := dominos.AssembleToppings(product)
+		if err != nil {
+			return nil, fmt.Errorf("product '%s': %w", product.ID, err)
+		}
+
 		products = append(products, dominos.Product{
-			ID: product.ID,
+			ID:       product.ID,
+			Quantity: product.Quantity,
+			Toppings: toppings,
 		})
 	}
 
-	return &dominos.Order{
+	dominosOrder := &dominos.Order{
 		StoreID: store.Spec.ID,
 		PersonalInformation: dominos.PersonalInformation{
 			FirstName: customer.Spec.FirstName,
@@ -172,17 +830,113 @@ func (r *PizzaOrderReconciler) AssembleDominosOrder(
 			Email:     customer.Spec.Email,
 			Phone:     customer.Spec.Phone,
 		},
-		CreditCard: *cc,
-		Address: dominos.Address{
-			StreetName:   customer.Spec.StreetName,
-			StreetNumber: customer.Spec.StreetNumber,
-			City:         customer.Spec.City,
-			State:        customer.Spec.State,
-			Zip:          customer.Spec.Zip,
-		},
-		Products: products,
-		Service:  dominos.ServiceCarryout,
-	}, nil
+		Address:     dominos.CustomerAddress(customer),
+		Products:    products,
+		Coupons:     order.Spec.Coupons,
+		Service:     dominos.OrderServiceMethod(order),
+		PaymentType: dominos.OrderPaymentType(order),
+	}
+
+	// payment details are only needed (and only fetched) for placing the
+	// order - pricing goes without them.
+	//
+	if order.Spec.YeahSurePlaceTheOrder {
+		switch dominosOrder.PaymentType {
+		case dominos.PaymentTypeDoorCredit, dominos.PaymentTypeCreditCard:
+			cc, err := r.GetCreditCardInfo(ctx,
+				customer.Spec.CreditCardSecretRef.Name,
+				order.Namespace,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("get credit card info: %w", err)
+			}
+
+			dominosOrder.CreditCard = *cc
+		case dominos.PaymentTypeGiftCard:
+			gc, err := r.GetGiftCardInfo(ctx,
+				customer.Spec.GiftCardSecretRef.Name,
+				order.Namespace,
+			)
+			if err != nil {
+				return nil, fmt.Errorf("get gift card info: %w", err)
+			}
+
+			dominosOrder.GiftCard = *gc
+		}
+	}
+
+	return dominosOrder, nil
+}
+
+// ValidateServiceMethod checks whether a store takes orders for a service
+// method.
+//
+// Stores that don't list the service methods they support are assumed to
+// support them all.
+func ValidateServiceMethod(store *v1alpha1.PizzaStore, service dominos.Service) error {
+	if len(store.Spec.ServiceMethods) == 0 {
+		return nil
+	}
+
+	for _, method := range store.Spec.ServiceMethods {
+		if method == string(service) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("store '%s' doesn't take %s orders (only %s)",
+		store.Spec.ID, service, strings.Join(store.Spec.ServiceMethods, ", "),
+	)
+}
+
+// ValidatePaymentType checks whether a store accepts a payment type.
+//
+// Stores that don't list the payment types they accept are assumed to accept
+// them all.
+func ValidatePaymentType(store *v1alpha1.PizzaStore, paymentType dominos.PaymentType) error {
+	if len(store.Spec.PaymentTypes) == 0 {
+		return nil
+	}
+
+	for _, accepted := range store.Spec.PaymentTypes {
+		if accepted == string(paymentType) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("store '%s' doesn't accept %s payments (only %s)",
+		store.Spec.ID, paymentType, strings.Join(store.Spec.PaymentTypes, ", "),
+	)
+}
+
+// AssemblePaymentStatus describes how an order got paid for, without
+// revealing more than the last digits of the card used.
+func AssemblePaymentStatus(order *dominos.Order) *v1alpha1.PizzaOrderPayment {
+	payment := &v1alpha1.PizzaOrderPayment{
+		Type: string(order.PaymentType),
+	}
+
+	switch order.PaymentType {
+	case dominos.PaymentTypeDoorCredit:
+		payment.CardType = string(order.CreditCard.Type)
+	case dominos.PaymentTypeCreditCard:
+		payment.CardType = string(order.CreditCard.Type)
+		payment.CardLastDigits = lastDigits(order.CreditCard.Number)
+	case dominos.PaymentTypeGiftCard:
+		payment.CardLastDigits = lastDigits(order.GiftCard.Number)
+	}
+
+	return payment
+}
+
+func lastDigits(number string) string {
+	const n = 4
+
+	if len(number) <= n {
+		return number
+	}
+
+	return number[len(number)-n:]
 }
 
 func (r *PizzaOrderReconciler) GetCreditCardInfo(
@@ -222,8 +976,10 @@ func (r *PizzaOrderReconciler) GetCreditCardInfo(
 		cardType = dominos.CreditCardTypeMastercard
 	case "visa":
 		cardType = dominos.CreditCardTypeVisa
+	case "amex":
+		cardType = dominos.CreditCardTypeAmex
 	default:
-		return nil, fmt.Errorf("unknown card type '%s'", cardType)
+		return nil, fmt.Errorf("unknown card type '%s'", cardTypeStr)
 	}
 
 	zip, found := obj.Data["zip"]
@@ -240,6 +996,34 @@ func (r *PizzaOrderReconciler) GetCreditCardInfo(
 	}, nil
 }
 
+func (r *PizzaOrderReconciler) GetGiftCardInfo(
+	ctx context.Context,
+	name, namespace string,
+) (*dominos.GiftCard, error) {
+	obj := &corev1.Secret{}
+	if err := r.Client.Get(ctx, client.ObjectKey{
+		Name:      name,
+		Namespace: namespace,
+	}, obj); err != nil {
+		return nil, fmt.Errorf("get: %w", err)
+	}
+
+	number, found := obj.Data["number"]
+	if !found {
+		return nil, fmt.Errorf("'number' not found in gift card info")
+	}
+
+	pin, found := obj.Data["pin"]
+	if !found {
+		return nil, fmt.Errorf("'pin' not found in gift card info")
+	}
+
+	return &dominos.GiftCard{
+		Number: string(number),
+		PIN:    string(pin),
+	}, nil
+}
+
 func (r *PizzaOrderReconciler) GetPizzaStore(
 	ctx context.Context,
 	name, namespace string,
@@ -285,9 +1069,35 @@ func (r *PizzaOrderReconciler) GetPizzaOrder(
 	return obj, nil
 }
 
-func (r *PizzaOrderReconciler) IsOrderAlreadyPriced(order *v1alpha1.PizzaOrder) bool {
-	for _, cond := range order.Status.Conditions {
-		if cond.Type == "OrderPriced" {
+// IsOrderAlreadyPriced tells whether the order has been priced for what's
+// currently in its spec.
+func (r *PizzaOrderReconciler) IsOrderAlreadyPriced(order *v1alpha1.PizzaOrder, fingerprint string) bool {
+	return meta.IsStatusConditionTrue(order.Status.Conditions, "OrderPriced") &&
+		order.Status.PricedFingerprint == fingerprint
+}
+
+// IsPlacementUnconfirmed tells whether the order is being (or might have
+// been) placed, in which case it must not be priced nor placed again unless
+// asked to (see IsPlacementRetryRequested).
+func (r *PizzaOrderReconciler) IsPlacementUnconfirmed(order *v1alpha1.PizzaOrder) bool {
+	placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+	return placed != nil && placed.Status == metav1.ConditionUnknown
+}
+
+// IsPlacementRetryRequested tells whether an order has been annotated with
+// RetryPlacementAnnotation.
+func IsPlacementRetryRequested(order *v1alpha1.PizzaOrder) bool {
+	_, retry := order.Annotations[RetryPlacementAnnotation]
+	return retry
+}
+
+// IsOrderRejected tells whether the order couldn't be priced or placed (see
+// RejectOrder), in which case there's no point in reconciling it again until
+// it changes.
+func IsOrderRejected(order *v1alpha1.PizzaOrder) bool {
+	for _, conditionType := range []string{"OrderPriced", "OrderPlaced"} {
+		cond := meta.FindStatusCondition(order.Status.Conditions, conditionType)
+		if cond != nil && cond.Status == metav1.ConditionFalse {
 			return true
 		}
 	}
@@ -295,12 +1105,32 @@ func (r *PizzaOrderReconciler) IsOrderAlreadyPriced(order *v1alpha1.PizzaOrder)
 	return false
 }
 
-func (r *PizzaOrderReconciler) IsOrderAlreadyPlaced(order *v1alpha1.PizzaOrder) bool {
-	for _, cond := range order.Status.Conditions {
-		if cond.Type == "OrderPlaced" {
-			return true
-		}
+// IsPlacementRejected tells whether Domino's refused to place the order as
+// it currently is (i.e., since its spec last changed).
+func IsPlacementRejected(order *v1alpha1.PizzaOrder) bool {
+	placed := meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced")
+	if placed == nil || placed.Status != metav1.ConditionFalse {
+		return false
 	}
 
-	return false
+	return placed.ObservedGeneration == order.Generation && !placementPrechecks[placed.Reason]
+}
+
+// placementPrechecks are the reasons for an order not to have been placed
+// that come from the controller rather than from Domino's, and that get
+// checked again whenever the order is reconciled.
+var placementPrechecks = map[string]bool{
+	"PriceNotAcknowledged": true,
+	"InvalidOrder":         true,
+	"NotSent":              true,
+}
+
+func (r *PizzaOrderReconciler) IsOrderAlreadyPlaced(order *v1alpha1.PizzaOrder) bool {
+	return meta.IsStatusConditionTrue(order.Status.Conditions, "OrderPlaced")
+}
+
+// IsOrderComplete tells whether an order is done being tracked: either it's
+// complete, or it can't be told whether it is.
+func IsOrderComplete(order *v1alpha1.PizzaOrder) bool {
+	return meta.FindStatusCondition(order.Status.Conditions, "Complete") != nil
 }
@@ -0,0 +1,532 @@
+package reconciler_test
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
+	"github.com/cirocosta/pizza-controller/pkg/dominos/dominostest"
+	"github.com/cirocosta/pizza-controller/pkg/reconciler"
+)
+
+const namespace = "default"
+
+// orderTest is a PizzaOrderReconciler backed by a fake Kubernetes API
+// (holding a customer, a store, and an order for it) and a fake Domino's.
+type orderTest struct {
+	t        *testing.T
+	srv      *dominostest.Server
+	client   client.Client
+	recorder *record.FakeRecorder
+	r        *reconciler.PizzaOrderReconciler
+
+	// result is what the last reconciliation resulted in.
+	result ctrl.Result
+}
+
+func newOrderTest(t *testing.T, objs ...runtime.Object) *orderTest {
+	srv := dominostest.NewServer()
+	t.Cleanup(srv.Close)
+
+	srv.AddStore(dominostest.Store{
+		ID:       "10391",
+		Carryout: true,
+		TaxRate:  0.13,
+		Products: []dominostest.Product{
+			{Code: "10SCREEN", Name: "Small Hand Tossed", Price: 9.03},
+		},
+	})
+
+	scheme := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+	if err := v1alpha1.AddToScheme(scheme); err != nil {
+		t.Fatal(err)
+	}
+
+	objs = append(objs,
+		&v1alpha1.PizzaCustomer{
+			ObjectMeta: metav1.ObjectMeta{Name: "barack", Namespace: namespace},
+			Spec: v1alpha1.PizzaCustomerSpec{
+				FirstName:    "barack",
+				LastName:     "obama",
+				Email:        "barack@example.com",
+				Phone:        "416-555-0199",
+				StreetNumber: "90",
+				StreetName:   "Bremner Blvd",
+				City:         "Toronto",
+				State:        "ON",
+				Zip:          "M5J 0A9",
+			},
+		},
+		&v1alpha1.PizzaStore{
+			ObjectMeta: metav1.ObjectMeta{Name: "store-10391", Namespace: namespace},
+			Spec: v1alpha1.PizzaStoreSpec{
+				ID:             "10391",
+				ServiceMethods: []string{"Carryout"},
+			},
+		},
+		&v1alpha1.PizzaOrder{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ma-pizza",
+				Namespace: namespace,
+				UID:       types.UID("4b0bd6d5-4b1e-4d0e-9c1a-62a8d1f0e0c7"),
+			},
+			Spec: v1alpha1.PizzaOrderSpec{
+				StoreRef:      corev1.LocalObjectReference{Name: "store-10391"},
+				CustomerRef:   corev1.LocalObjectReference{Name: "barack"},
+				ServiceMethod: "Carryout",
+				PaymentType:   "Cash",
+				Products: []v1alpha1.PizzaOrderProduct{
+					{ID: "10SCREEN", Quantity: 1},
+				},
+			},
+		},
+	)
+
+	c := fake.NewFakeClientWithScheme(scheme, objs...)
+	recorder := record.NewFakeRecorder(100)
+
+	return &orderTest{
+		t:        t,
+		srv:      srv,
+		client:   c,
+		recorder: recorder,
+		r: &reconciler.PizzaOrderReconciler{
+			Log:       log.NullLogger{},
+			Client:    c,
+			APIReader: c,
+			Recorder:  recorder,
+			Dominos: dominos.Config{
+				URL:         srv.URL,
+				RetryPolicy: dominos.NoRetries,
+			},
+		},
+	}
+}
+
+func (o *orderTest) reconcile() *v1alpha1.PizzaOrder {
+	o.t.Helper()
+
+	result, err := o.r.Reconcile(context.Background(), ctrl.Request{
+		NamespacedName: types.NamespacedName{Name: "ma-pizza", Namespace: namespace},
+	})
+	if err != nil {
+		o.t.Fatalf("reconcile: %v", err)
+	}
+
+	o.result = result
+
+	return o.order()
+}
+
+func (o *orderTest) order() *v1alpha1.PizzaOrder {
+	o.t.Helper()
+
+	order := &v1alpha1.PizzaOrder{}
+	if err := o.client.Get(context.Background(), client.ObjectKey{
+		Name: "ma-pizza", Namespace: namespace,
+	}, order); err != nil {
+		o.t.Fatalf("get order: %v", err)
+	}
+
+	return order
+}
+
+func (o *orderTest) update(mutate func(*v1alpha1.PizzaOrder)) {
+	o.t.Helper()
+
+	order := o.order()
+	mutate(order)
+
+	if err := o.client.Update(context.Background(), order); err != nil {
+		o.t.Fatalf("update order: %v", err)
+	}
+}
+
+func (o *orderTest) expectCondition(order *v1alpha1.PizzaOrder, conditionType string, status metav1.ConditionStatus, reason string) {
+	o.t.Helper()
+
+	cond := meta.FindStatusCondition(order.Status.Conditions, conditionType)
+	if cond == nil {
+		o.t.Fatalf("expected condition %s to be set, got %+v", conditionType, order.Status.Conditions)
+	}
+
+	if cond.Status != status || cond.Reason != reason {
+		o.t.Fatalf("expected condition %s to be %s (%s), got %s (%s: %s)",
+			conditionType, status, reason, cond.Status, cond.Reason, cond.Message,
+		)
+	}
+}
+
+func TestPizzaOrderReconciler(t *testing.T) {
+	o := newOrderTest(t)
+
+	// orders get priced, but not placed unless asked to ...
+	//
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPriced", metav1.ConditionTrue, "OrderPriced")
+
+	if order.Status.Price == nil || order.Status.Price.Total != "10.20" || order.Status.Price.Currency != "CAD" {
+		t.Fatalf("expected the order to be priced at 10.20 CAD, got %+v", order.Status.Price)
+	}
+
+	if meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced") != nil {
+		t.Fatalf("expected the order not to be placed, got %+v", order.Status.Conditions)
+	}
+
+	// ... and even then, not without the price having been agreed to ...
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.00"
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionFalse, "PriceNotAcknowledged")
+
+	if o.result != (ctrl.Result{}) {
+		t.Errorf("expected the order not to be requeued, got %+v", o.result)
+	}
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 0 {
+		t.Fatalf("expected the order not to be placed, got %d request(s)", n)
+	}
+
+	// ... at which point it gets placed ...
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.AcknowledgedPrice = "10.2"
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionTrue, "OrderPlaced")
+
+	placed := o.srv.Orders()
+	if len(placed) != 1 {
+		t.Fatalf("expected 1 order to have been placed, got %d", len(placed))
+	}
+
+	if order.Status.OrderID != placed[0].ID || order.Status.OrderKey != placed[0].Key {
+		t.Errorf("expected order %s (key %s), got %s (key %s)",
+			placed[0].ID, placed[0].Key, order.Status.OrderID, order.Status.OrderKey,
+		)
+	}
+
+	if placed[0].Amount != 1020 {
+		t.Errorf("expected the order to be paid 10.20, got %s", placed[0].Amount)
+	}
+
+	// ... only once, and then tracked.
+	//
+	order = o.reconcile()
+	o.expectCondition(order, "Making", metav1.ConditionTrue, "Making")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Errorf("expected the order to be placed once, got %d request(s)", n)
+	}
+}
+
+func TestPizzaOrderReconcilerPricingRejected(t *testing.T) {
+	o := newOrderTest(t)
+
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.Products = []v1alpha1.PizzaOrderProduct{
+			{ID: "14SCREEN", Quantity: 1},
+		}
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPriced", metav1.ConditionFalse, "InvalidProducts")
+
+	if o.result != (ctrl.Result{}) {
+		t.Errorf("expected the order not to be requeued, got %+v", o.result)
+	}
+
+	if n := o.srv.Requests(dominostest.EndpointPriceOrder); n != 0 {
+		t.Errorf("expected the order not to be priced, got %d request(s)", n)
+	}
+}
+
+func TestPizzaOrderReconcilerRepricing(t *testing.T) {
+	o := newOrderTest(t)
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPriced", metav1.ConditionTrue, "OrderPriced")
+	fingerprint := order.Status.PricedFingerprint
+
+	// orders priced for what's in their spec don't get priced again ...
+	//
+	o.reconcile()
+	if n := o.srv.Requests(dominostest.EndpointPriceOrder); n != 1 {
+		t.Fatalf("expected the order to be priced once, got %d request(s)", n)
+	}
+
+	// ... unless the customer moves, which might change what it costs.
+	//
+	customer := &v1alpha1.PizzaCustomer{}
+	if err := o.client.Get(context.Background(), client.ObjectKey{
+		Name: "barack", Namespace: namespace,
+	}, customer); err != nil {
+		t.Fatalf("get customer: %v", err)
+	}
+
+	customer.Spec.StreetNumber = "100"
+	if err := o.client.Update(context.Background(), customer); err != nil {
+		t.Fatalf("update customer: %v", err)
+	}
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPriced", metav1.ConditionTrue, "OrderPriced")
+
+	if n := o.srv.Requests(dominostest.EndpointPriceOrder); n != 2 {
+		t.Errorf("expected the order to be priced again, got %d request(s)", n)
+	}
+
+	if order.Status.PricedFingerprint == fingerprint {
+		t.Errorf("expected the fingerprint to change with the address")
+	}
+}
+
+func TestPizzaOrderReconcilerPlacementUnconfirmed(t *testing.T) {
+	o := newOrderTest(t)
+
+	o.reconcile()
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.20"
+	})
+
+	// the order goes through, but the response to placing it doesn't
+	// make it back ...
+	//
+	o.srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+		StatusCode:   http.StatusBadGateway,
+		AfterPlacing: true,
+		Times:        1,
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionUnknown, "PlacementUnconfirmed")
+
+	// ... so it's left alone ...
+	//
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionUnknown, "PlacementUnconfirmed")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Fatalf("expected the order not to be placed again, got %d request(s)", n)
+	}
+
+	// ... until asked to be placed again, at which point the tracker
+	// tells that it had been placed after all.
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Annotations = map[string]string{
+			reconciler.RetryPlacementAnnotation: "true",
+		}
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionTrue, "OrderPlaced")
+
+	placed := o.srv.Orders()
+	if len(placed) != 1 || order.Status.OrderID != placed[0].ID {
+		t.Fatalf("expected order %s to be the only one placed, got %+v", order.Status.OrderID, placed)
+	}
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Errorf("expected the order not to be placed again, got %d request(s)", n)
+	}
+
+	if _, found := order.Annotations[reconciler.RetryPlacementAnnotation]; found {
+		t.Errorf("expected the retry annotation to be removed")
+	}
+}
+
+func TestPizzaOrderReconcilerPlacementUnconfirmedNotFound(t *testing.T) {
+	o := newOrderTest(t)
+
+	o.reconcile()
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.20"
+	})
+
+	// the order doesn't go through, but that can't be told from the
+	// response ...
+	//
+	o.srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+		StatusCode: http.StatusBadGateway,
+		Times:      1,
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionUnknown, "PlacementUnconfirmed")
+
+	// ... nor from the tracker not finding it ...
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Annotations = map[string]string{
+			reconciler.RetryPlacementAnnotation: "true",
+		}
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionUnknown, "PlacementUnconfirmed")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Fatalf("expected the order not to be placed again, got %d request(s)", n)
+	}
+
+	if _, found := order.Annotations[reconciler.RetryPlacementAnnotation]; found {
+		t.Errorf("expected the retry annotation to be removed")
+	}
+
+	// ... so it's only placed again once confirmed not to have been.
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Annotations = map[string]string{
+			reconciler.RetryPlacementAnnotation: reconciler.RetryPlacementNotPlaced,
+		}
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionTrue, "OrderPlaced")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 2 {
+		t.Errorf("expected the order to be placed again, got %d request(s)", n)
+	}
+
+	if placed := o.srv.Orders(); len(placed) != 1 || order.Status.OrderID != placed[0].ID {
+		t.Errorf("expected order %s to be the only one placed, got %+v", order.Status.OrderID, placed)
+	}
+}
+
+func TestPizzaOrderReconcilerPlacementUnconfirmedRepriced(t *testing.T) {
+	o := newOrderTest(t)
+
+	o.reconcile()
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.20"
+	})
+
+	o.srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+		StatusCode:   http.StatusBadGateway,
+		AfterPlacing: true,
+		Times:        1,
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionUnknown, "PlacementUnconfirmed")
+
+	// the order might have been placed, so nothing about it changing
+	// (like its price no longer being acknowledged) gets acted on before
+	// checking with the tracker.
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.AcknowledgedPrice = "9.99"
+		order.Annotations = map[string]string{
+			reconciler.RetryPlacementAnnotation: "true",
+		}
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionTrue, "OrderPlaced")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Errorf("expected the order not to be placed again, got %d request(s)", n)
+	}
+}
+
+func TestPizzaOrderReconcilerPlacementRejected(t *testing.T) {
+	o := newOrderTest(t)
+
+	o.reconcile()
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.20"
+	})
+
+	// Domino's refuses to place the order ...
+	//
+	o.srv.Fail(dominostest.EndpointPlaceOrder, dominostest.Failure{
+		Code:  "StoreClosed",
+		Times: 1,
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionFalse, "StoreClosed")
+
+	if o.result != (ctrl.Result{}) {
+		t.Errorf("expected the order not to be requeued, got %+v", o.result)
+	}
+
+	// ... which it's not asked to again on its own (e.g., once the store
+	// reopens) ...
+	//
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionFalse, "StoreClosed")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 1 {
+		t.Fatalf("expected the order not to be placed again, got %d request(s)", n)
+	}
+
+	// ... but only when asked to.
+	//
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Annotations = map[string]string{
+			reconciler.RetryPlacementAnnotation: "true",
+		}
+	})
+
+	order = o.reconcile()
+	o.expectCondition(order, "OrderPlaced", metav1.ConditionTrue, "OrderPlaced")
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 2 {
+		t.Errorf("expected the order to be placed again, got %d request(s)", n)
+	}
+}
+
+func TestPizzaOrderReconcilerBudget(t *testing.T) {
+	o := newOrderTest(t, &v1alpha1.PizzaBudget{
+		ObjectMeta: metav1.ObjectMeta{Name: "team-lunch", Namespace: namespace},
+		Spec: v1alpha1.PizzaBudgetSpec{
+			Amount: "10.00",
+			Period: "Daily",
+		},
+	})
+
+	o.reconcile()
+	o.update(func(order *v1alpha1.PizzaOrder) {
+		order.Spec.YeahSurePlaceTheOrder = true
+		order.Spec.AcknowledgedPrice = "10.20"
+	})
+
+	order := o.reconcile()
+	o.expectCondition(order, "BudgetExceeded", metav1.ConditionTrue, "BudgetExhausted")
+
+	if meta.FindStatusCondition(order.Status.Conditions, "OrderPlaced") != nil {
+		t.Fatalf("expected the order not to be placed, got %+v", order.Status.Conditions)
+	}
+
+	if n := o.srv.Requests(dominostest.EndpointPlaceOrder); n != 0 {
+		t.Errorf("expected the order not to be placed, got %d request(s)", n)
+	}
+}
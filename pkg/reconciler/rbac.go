@@ -1,9 +1,13 @@
 package reconciler
 
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzacustomers,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzacustomers/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzaorders,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzaorders/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzastores,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ops.tips,resources=pizzastores/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ops.tips,resources=pizzapolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ops.tips,resources=pizzabudgets,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ops.tips,resources=pizzabudgets/status,verbs=get;update;patch
@@ -1,16 +1,21 @@
 package reconciler
 
 import (
+	"context"
 	"fmt"
 
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 	"sigs.k8s.io/controller-runtime/pkg/source"
 
 	v1alpha1 "github.com/cirocosta/pizza-controller/pkg/apis/ops.tips/v1alpha1"
+	"github.com/cirocosta/pizza-controller/pkg/dominos"
 )
 
 func AddToScheme(scheme *runtime.Scheme) error {
@@ -25,23 +30,30 @@ func AddToScheme(scheme *runtime.Scheme) error {
 	return nil
 }
 
-func RegisterReconcilers(mgr manager.Manager) error {
-	// if err := RegisterPizzaCustomerReconciler(mgr); err != nil {
-	// 	return fmt.Errorf("register pizza customer reconciler: %w")
-	// }
+func RegisterReconcilers(mgr manager.Manager, dominosConfig dominos.Config, maxNearbyStores int) error {
+	if err := RegisterPizzaCustomerReconciler(mgr, dominosConfig, maxNearbyStores); err != nil {
+		return fmt.Errorf("register pizza customer reconciler: %w", err)
+	}
+
+	if err := RegisterPizzaOrderReconciler(mgr, dominosConfig); err != nil {
+		return fmt.Errorf("register pizza order reconciler: %w", err)
+	}
 
-	if err := RegisterPizzaOrderReconciler(mgr); err != nil {
-		return fmt.Errorf("register pizza order reconciler: %w")
+	if err := RegisterPizzaBudgetReconciler(mgr); err != nil {
+		return fmt.Errorf("register pizza budget reconciler: %w", err)
 	}
 
 	return nil
 }
 
-func RegisterPizzaOrderReconciler(mgr manager.Manager) error {
+func RegisterPizzaOrderReconciler(mgr manager.Manager, dominosConfig dominos.Config) error {
 	c, err := controller.New("pizza-order-reconciler", mgr, controller.Options{
 		Reconciler: &PizzaOrderReconciler{
-			Log:    mgr.GetLogger().WithName("pizza-order-reconciler"),
-			Client: mgr.GetClient(),
+			Log:       mgr.GetLogger().WithName("pizza-order-reconciler"),
+			Client:    mgr.GetClient(),
+			Recorder:  mgr.GetEventRecorderFor("pizza-order-reconciler"),
+			Dominos:   dominosConfig,
+			APIReader: mgr.GetAPIReader(),
 		},
 	})
 	if err != nil {
@@ -58,11 +70,15 @@ func RegisterPizzaOrderReconciler(mgr manager.Manager) error {
 	return nil
 }
 
-func RegisterPizzaCustomerReconciler(mgr manager.Manager) error {
+func RegisterPizzaCustomerReconciler(mgr manager.Manager, dominosConfig dominos.Config, maxNearbyStores int) error {
 	c, err := controller.New("pizza-customer-reconciler", mgr, controller.Options{
 		Reconciler: &PizzaCustomerReconciler{
-			Log:    mgr.GetLogger().WithName("pizza-customer-reconciler"),
-			Client: mgr.GetClient(),
+			Log:             mgr.GetLogger().WithName("pizza-customer-reconciler"),
+			Client:          mgr.GetClient(),
+			Scheme:          mgr.GetScheme(),
+			Recorder:        mgr.GetEventRecorderFor("pizza-customer-reconciler"),
+			Dominos:         dominosConfig,
+			MaxNearbyStores: maxNearbyStores,
 		},
 	})
 	if err != nil {
@@ -76,5 +92,67 @@ func RegisterPizzaCustomerReconciler(mgr manager.Manager) error {
 		return fmt.Errorf("watch: %w", err)
 	}
 
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.PizzaStore{}},
+		&handler.EnqueueRequestForOwner{
+			OwnerType:    &v1alpha1.PizzaCustomer{},
+			IsController: false,
+		},
+	); err != nil {
+		return fmt.Errorf("watch stores: %w", err)
+	}
+
 	return nil
 }
+
+func RegisterPizzaBudgetReconciler(mgr manager.Manager) error {
+	c, err := controller.New("pizza-budget-reconciler", mgr, controller.Options{
+		Reconciler: &PizzaBudgetReconciler{
+			Log:    mgr.GetLogger().WithName("pizza-budget-reconciler"),
+			Client: mgr.GetClient(),
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("new controller: %w", err)
+	}
+
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.PizzaBudget{}},
+		&handler.EnqueueRequestForObject{},
+	); err != nil {
+		return fmt.Errorf("watch: %w", err)
+	}
+
+	// any order changing might change how much of the budgets of its
+	// namespace has been consumed.
+	//
+	if err := c.Watch(
+		&source.Kind{Type: &v1alpha1.PizzaOrder{}},
+		handler.EnqueueRequestsFromMapFunc(func(obj handler.MapObject) []reconcile.Request {
+			return budgetsInNamespace(mgr.GetClient(), obj.Object.GetNamespace())
+		}),
+	); err != nil {
+		return fmt.Errorf("watch orders: %w", err)
+	}
+
+	return nil
+}
+
+func budgetsInNamespace(c client.Client, namespace string) []reconcile.Request {
+	budgets := &v1alpha1.PizzaBudgetList{}
+	if err := c.List(context.Background(), budgets, client.InNamespace(namespace)); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for _, budget := range budgets.Items {
+		requests = append(requests, reconcile.Request{
+			NamespacedName: types.NamespacedName{
+				Name:      budget.Name,
+				Namespace: budget.Namespace,
+			},
+		})
+	}
+
+	return requests
+}